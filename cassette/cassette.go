@@ -0,0 +1,298 @@
+// Package cassette provides a record and replay xero.HTTPDoer, to run integration tests against captured Xero
+// responses.
+//
+// A cassette is a JSONL file with one Interaction per line. Recorders capture the requests and responses of another
+// HTTPDoer into a cassette, replayers serve them back without any network access.
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/luca-arch/code-drills/xero"
+)
+
+var (
+	ErrInvalidCassette = errors.New("invalid cassette")                    // Error returned when a cassette cannot be read.
+	ErrNoInteraction   = errors.New("no recorded interaction for request") // Error returned by replayers for unexpected requests.
+)
+
+// Interaction is a request and its response, as stored in a cassette.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Requests are matched on method, path and query.
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"` // Encoded with sorted keys.
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Response is a recorded response.
+// Bodies that are not valid UTF-8, such as gzipped ones, are stored in base64 and their Encoding is EncodingBase64.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+	Encoding   string      `json:"encoding,omitempty"` // Empty for bodies stored as they are.
+}
+
+// EncodingBase64 is the Encoding of the response bodies stored in standard base64.
+const EncodingBase64 = "base64"
+
+// newResponse returns the recorded response, the body is stored in base64 if it is not valid UTF-8.
+func newResponse(resp *http.Response, body []byte) Response {
+	recorded := Response{StatusCode: resp.StatusCode, Header: scrub(resp.Header), Body: "", Encoding: ""}
+
+	if body = scrubBody(body); utf8.Valid(body) {
+		recorded.Body = string(body)
+	} else {
+		recorded.Body = base64.StdEncoding.EncodeToString(body)
+		recorded.Encoding = EncodingBase64
+	}
+
+	return recorded
+}
+
+// body returns the recorded body, decoded.
+func (r Response) body() ([]byte, error) {
+	switch r.Encoding {
+	case "":
+		return []byte(r.Body), nil
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(r.Body) //nolint:wrapcheck // Wrapped by Replayer
+	default:
+		return nil, errors.New("unknown body encoding " + r.Encoding) //nolint:err113 // Wrapped by Replayer
+	}
+}
+
+// matches returns whether the request has the same method, path and query.
+func (r Request) matches(req *http.Request) bool {
+	return r.Method == req.Method && r.Path == req.URL.Path && r.Query == req.URL.Query().Encode()
+}
+
+// String describes the request as "METHOD path?query".
+func (r Request) String() string {
+	if r.Query == "" {
+		return r.Method + " " + r.Path
+	}
+
+	return r.Method + " " + r.Path + "?" + r.Query
+}
+
+// recorder captures the interactions of an HTTPDoer.
+type recorder struct {
+	mu   sync.Mutex
+	next xero.HTTPDoer
+	w    io.Writer
+}
+
+// Recorder returns an HTTPDoer that sends the requests through next, and writes every response to w as a line of
+// JSON. Credentials, that is xero.SensitiveHeaders() and the xero.SensitiveFields() of JSON bodies, are scrubbed before
+// anything is written, the caller gets the response unchanged.
+// Requests failing before a response is received are not recorded.
+func Recorder(next xero.HTTPDoer, w io.Writer) *recorder { //nolint:revive // Ok to return this unexported struct
+	return &recorder{
+		mu:   sync.Mutex{},
+		next: next,
+		w:    w,
+	}
+}
+
+// Do satisfies the xero.HTTPDoer interface.
+func (r *recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return resp, err //nolint:wrapcheck // Errors of the recorded HTTPDoer are returned as they are
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, errors.Join(xero.ErrRequestFailure, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var line bytes.Buffer
+
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false) // Keeps query strings readable.
+
+	err = enc.Encode(Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query().Encode(),
+			Header: scrub(req.Header),
+		},
+		Response: newResponse(resp, body),
+	})
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCassette, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err = r.w.Write(line.Bytes()); err != nil {
+		return nil, errors.Join(ErrInvalidCassette, err)
+	}
+
+	return resp, nil
+}
+
+// replayer serves the interactions of a cassette.
+type replayer struct {
+	bodies       [][]byte // Decoded response bodies, by interaction.
+	interactions []Interaction
+	mu           sync.Mutex
+	used         []bool
+}
+
+// Replayer returns an HTTPDoer that serves the interactions read from r.
+// Each interaction is served once, in the order they were recorded, to the first request with the same method, path
+// and query. Requests without an interaction left fail with ErrNoInteraction.
+func Replayer(r io.Reader) (*replayer, error) { //nolint:revive // Ok to return this unexported struct
+	var (
+		bodies       [][]byte
+		interactions []Interaction
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20) //nolint:mnd // Recorded bodies can be large
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, errors.Join(ErrInvalidCassette, errors.New("line "+strconv.Itoa(line)), err) //nolint:err113 // Detail of ErrInvalidCassette
+		}
+
+		body, err := interaction.Response.body()
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCassette, errors.New("line "+strconv.Itoa(line)), err) //nolint:err113 // Detail of ErrInvalidCassette
+		}
+
+		bodies = append(bodies, body)
+		interactions = append(interactions, interaction)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(ErrInvalidCassette, err)
+	}
+
+	return &replayer{
+		bodies:       bodies,
+		interactions: interactions,
+		mu:           sync.Mutex{},
+		used:         make([]bool, len(interactions)),
+	}, nil
+}
+
+// Do satisfies the xero.HTTPDoer interface.
+func (r *replayer) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !interaction.Request.matches(req) {
+			continue
+		}
+
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        strconv.Itoa(interaction.Response.StatusCode) + " " + http.StatusText(interaction.Response.StatusCode),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(r.bodies[i])),
+			ContentLength: int64(len(r.bodies[i])),
+			Request:       req,
+		}, nil
+	}
+
+	got := Request{Method: req.Method, Path: req.URL.Path, Query: req.URL.Query().Encode(), Header: nil}
+
+	return nil, errors.Join(ErrNoInteraction, errors.New(got.String())) //nolint:err113 // Detail of ErrNoInteraction
+}
+
+// Unused returns the interactions that have not been served, so that tests can check every one was expected.
+func (r *replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unused := []Interaction{}
+
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+// scrub returns a copy of the header with the values of the sensitive headers replaced by xero.Redacted.
+func scrub(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	headers := xero.SensitiveHeaders()
+
+	for name := range scrubbed {
+		if slices.ContainsFunc(headers, func(sensitive string) bool { return strings.EqualFold(name, sensitive) }) {
+			scrubbed[name] = []string{xero.Redacted}
+		}
+	}
+
+	return scrubbed
+}
+
+// scrubBody replaces the tokens of JSON object bodies by xero.Redacted, other bodies are returned unchanged.
+func scrubBody(body []byte) []byte {
+	var fields map[string]json.RawMessage
+
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+
+	found := false
+
+	for _, name := range xero.SensitiveFields() {
+		if _, ok := fields[name]; ok {
+			fields[name] = json.RawMessage(strconv.Quote(xero.Redacted))
+			found = true
+		}
+	}
+
+	if !found {
+		return body
+	}
+
+	scrubbed, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+
+	return scrubbed
+}
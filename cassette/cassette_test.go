@@ -0,0 +1,175 @@
+package cassette_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/luca-arch/code-drills/cassette"
+	"github.com/luca-arch/code-drills/mockxero"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+const tenantID = "70784a63-d24b-46a9-a4db-0e70a274b056" // Served by mockxero.
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(mockxero.HTTPServer(nil).Mux())
+	t.Cleanup(srv.Close)
+
+	var tape bytes.Buffer
+
+	recorded, err := xero.HTTPClient(nil).
+		WithBaseURL(srv.URL).
+		WithHTTPClient(cassette.Recorder(http.DefaultClient, &tape)).
+		WithTenant(tenantID).
+		WithTokenSource(xero.StaticToken("secret-access-token")).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{Date: "2024-07-31", Periods: 2})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(tape.String(), "\n"))
+	assert.NotContains(t, tape.String(), "secret-access-token")
+	assert.Contains(t, tape.String(), `"Authorization":["REDACTED"]`)
+	assert.Contains(t, tape.String(), `"query":"date=2024-07-31&periods=2"`)
+
+	// Replayed without any server.
+	replayer, err := cassette.Replayer(&tape)
+	assert.NoError(t, err)
+
+	replayed, err := xero.HTTPClient(nil).
+		WithHTTPClient(replayer).
+		WithTenant(tenantID).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{Periods: 2, Date: "2024-07-31"})
+	assert.NoError(t, err)
+	assert.Equal(t, recorded.Reports, replayed.Reports)
+	assert.Empty(t, replayer.Unused())
+}
+
+func TestRecordAndReplayGzip(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+
+		w.Header().Set("Content-Encoding", "gzip")
+
+		zw := gzip.NewWriter(w)
+		defer zw.Close()
+
+		body, err := fs.ReadFile(mockxero.Fixtures(), "BalanceSheet/default.json")
+		assert.NoError(t, err)
+
+		_, _ = zw.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	var tape bytes.Buffer
+
+	// The recorder gets the compressed body, as it sits between AcceptGzip and the network.
+	recorded, err := xero.HTTPClient(nil).
+		WithBaseURL(srv.URL).
+		WithHTTPClient(cassette.Recorder(http.DefaultClient, &tape)).
+		WithMiddlewares(xero.AcceptGzip()).
+		WithTenant(tenantID).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.Contains(t, tape.String(), `"encoding":"base64"`)
+
+	replayer, err := cassette.Replayer(&tape)
+	assert.NoError(t, err)
+
+	replayed, err := xero.HTTPClient(nil).
+		WithHTTPClient(replayer).
+		WithMiddlewares(xero.AcceptGzip()).
+		WithTenant(tenantID).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, recorded.Reports, replayed.Reports)
+}
+
+func TestReplayer(t *testing.T) {
+	t.Parallel()
+
+	tape := `{"request":{"method":"GET","path":"/connections"},"response":{"statusCode":503,"body":""}}
+{"request":{"method":"GET","path":"/connections"},"response":{"statusCode":200,"body":"[]"}}
+{"request":{"method":"GET","path":"/api.xro/2.0/Reports/BalanceSheet","query":"date=2024-07-31"},"response":{"statusCode":200,"header":{"X-Minlimit-Remaining":["59"]},"body":"{}"}}
+`
+
+	tests := map[string]struct {
+		paths    []string
+		statuses []int
+		err      error
+		unused   int
+	}{
+		"in recorded order": {
+			paths:    []string{"/connections", "/connections"},
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			err:      nil,
+			unused:   1,
+		},
+		"query keys in any order": {
+			paths:    []string{"/api.xro/2.0/Reports/BalanceSheet?date=2024-07-31"},
+			statuses: []int{http.StatusOK},
+			err:      nil,
+			unused:   2,
+		},
+		"error - other query": {
+			paths: []string{"/api.xro/2.0/Reports/BalanceSheet?date=2024-06-30"},
+			err:   cassette.ErrNoInteraction,
+		},
+		"error - no interaction left": {
+			paths:    []string{"/connections", "/connections", "/connections"},
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			err:      cassette.ErrNoInteraction,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			replayer, err := cassette.Replayer(strings.NewReader(tape))
+			assert.NoError(t, err)
+
+			for i, path := range test.paths {
+				resp, err := replayer.Do(httptest.NewRequest(http.MethodGet, path, nil))
+
+				if i >= len(test.statuses) {
+					assert.ErrorIs(t, err, test.err)
+					assert.ErrorContains(t, err, "GET "+strings.Split(path, "?")[0])
+					assert.Nil(t, resp)
+
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, test.statuses[i], resp.StatusCode)
+
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, int64(len(body)), resp.ContentLength)
+			}
+
+			assert.Len(t, replayer.Unused(), test.unused)
+		})
+	}
+}
+
+func TestReplayerInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := cassette.Replayer(strings.NewReader("{\"request\":{}}\n\nnot JSON\n"))
+	assert.ErrorIs(t, err, cassette.ErrInvalidCassette)
+	assert.ErrorContains(t, err, "line 3")
+
+	_, err = cassette.Replayer(strings.NewReader(`{"request":{},"response":{"body":"not base64!","encoding":"base64"}}`))
+	assert.ErrorIs(t, err, cassette.ErrInvalidCassette)
+	assert.ErrorContains(t, err, "line 1")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/luca-arch/code-drills/mockxero"
+)
+
+func debugLogger() *slog.Logger {
+	lvl := new(slog.LevelVar)
+	lvl.Set(slog.LevelDebug)
+
+	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       lvl,
+		ReplaceAttr: nil,
+	})
+
+	return slog.New(handler)
+}
+
+// scenario reads the faults of the scenario file.
+func scenario(path string) (map[string]mockxero.Fault, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Reported as is
+	}
+
+	defer f.Close()
+
+	return mockxero.LoadScenario(f) //nolint:wrapcheck // Reported as is
+}
+
+func main() {
+	logger := debugLogger()
+
+	server := mockxero.HTTPServer(logger)
+
+	// Fixtures can be edited without rebuilding, see mockxero.Fixtures for the layout.
+	if dir := os.Getenv("MOCK_XERO_FIXTURES"); dir != "" {
+		server = server.WithFixtures(os.DirFS(dir))
+	}
+
+	// Faults can be changed at runtime too, see the /_mock/faults endpoints.
+	if path := os.Getenv("MOCK_XERO_SCENARIO"); path != "" {
+		faults, err := scenario(path)
+		if err != nil {
+			logger.Error("Could not load scenario", "path", path, "err", err)
+
+			os.Exit(1)
+		}
+
+		server = server.WithFaults(faults)
+	}
+
+	//nolint:gosec // "G114: Use of net/http serve function that has no support for setting timeouts" can be ignored for this demo
+	err := http.ListenAndServe(":3000", server.Mux())
+	if err != nil {
+		logger.Error(err.Error())
+
+		os.Exit(1)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/luca-arch/code-drills/metrics"
 	"github.com/luca-arch/code-drills/web"
 	"github.com/luca-arch/code-drills/xero"
 )
@@ -22,13 +24,45 @@ func debugLogger() *slog.Logger {
 	return slog.New(handler)
 }
 
+// tokenSource returns the Xero token source configured through environment variables, or nil when none is set.
+func tokenSource() xero.TokenSource { //nolint:ireturn // Token sources are only known by interface
+	cfg := xero.OAuthConfig{
+		ClientID:     os.Getenv("XERO_CLIENT_ID"),
+		ClientSecret: os.Getenv("XERO_CLIENT_SECRET"),
+		HTTPClient:   nil,
+		OnToken:      nil,
+		Scopes:       strings.Fields(os.Getenv("XERO_SCOPES")),
+		Timeout:      0,
+		TokenURL:     os.Getenv("XERO_TOKEN_URL"),
+	}
+
+	switch {
+	case os.Getenv("XERO_ACCESS_TOKEN") != "":
+		return xero.StaticToken(os.Getenv("XERO_ACCESS_TOKEN"))
+	case cfg.ClientID == "" || cfg.ClientSecret == "":
+		return nil
+	case os.Getenv("XERO_REFRESH_TOKEN") != "":
+		return xero.RefreshTokenSource(cfg, os.Getenv("XERO_REFRESH_TOKEN"))
+	default:
+		return xero.ClientCredentialsSource(cfg)
+	}
+}
+
 func main() {
 	logger := debugLogger()
+	registry := metrics.NewRegistry()
 
 	apiClient := xero.HTTPClient(logger).
-		WithBaseURL("http://mock-xero:3000")
+		WithBaseURL("http://mock-xero:3000").
+		WithCache(xero.DefaultCacheConfig()).
+		WithMetrics(registry).
+		WithMiddlewares(xero.UserAgent("code-drills-webserver"), xero.AcceptGzip()).
+		WithRetryPolicy(xero.DefaultRetryPolicy()).
+		WithTenant(os.Getenv("XERO_TENANT_ID")).
+		WithTokenSource(tokenSource())
 
-	server := web.HTTPServer(logger, apiClient)
+	server := web.HTTPServer(logger, web.CircuitBreaker(logger, apiClient, web.DefaultBreakerConfig())).
+		WithMetrics(registry)
 
 	//nolint:gosec // "G114: Use of net/http serve function that has no support for setting timeouts" can be ignored for this demo
 	err := http.ListenAndServe(":4000", server.Mux())
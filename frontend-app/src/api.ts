@@ -82,7 +82,7 @@ export const getReports = (
     }
 
     if (name === "date") {
-      httpParams.append(name, (value as Dayjs).toISOString());
+      httpParams.append(name, (value as Dayjs).format("YYYY-MM-DD"));
     } else {
       httpParams.append(name, value.toString());
     }
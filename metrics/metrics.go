@@ -0,0 +1,308 @@
+// Package metrics provides counters, histograms and gauges exposed in the Prometheus text format.
+// See https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histograms.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10} //nolint:gochecknoglobals,mnd // Same as the Prometheus clients
+
+// collector writes the samples of a metric family.
+type collector interface {
+	metricName() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the metrics exposed by Handler.
+type Registry struct {
+	collectors []collector
+	mu         sync.Mutex // Guards collectors.
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: nil,
+		mu:         sync.Mutex{},
+	}
+}
+
+// Counter registers a counter with the given label names.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{
+		family: newFamily(name, help, "counter", labels),
+		values: make(map[string]float64),
+	}
+
+	r.register(c)
+
+	return c
+}
+
+// Histogram registers a histogram with the given buckets, such as DefaultBuckets, and label names.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		buckets: slices.Sorted(slices.Values(buckets)),
+		family:  newFamily(name, help, "histogram", labels),
+		values:  make(map[string]*histogramValue),
+	}
+
+	r.register(h)
+
+	return h
+}
+
+// GaugeFunc registers a gauge whose values are collected by calling collect every time the metrics are written.
+// collect must call set once per series, with as many label values as label names.
+func (r *Registry) GaugeFunc(name, help string, labels []string, collect func(set func(value float64, labelValues ...string))) {
+	r.register(&gaugeFunc{
+		collect: collect,
+		family:  newFamily(name, help, "gauge", labels),
+	})
+}
+
+// Handler returns an HTTP handler that serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		_ = r.Write(w) // The client has gone away.
+	})
+}
+
+// Write writes every metric in the Prometheus text format, in the order they were registered.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	for _, c := range collectors {
+		c.write(bw)
+	}
+
+	return bw.Flush() //nolint:wrapcheck // Errors of the writer are returned as they are
+}
+
+// register adds the metric to the registry. It panics if a metric with the same name is already registered,
+// as both would be written under the same HELP and TYPE lines.
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.collectors {
+		if registered.metricName() == c.metricName() {
+			panic("metrics: " + c.metricName() + " is already registered")
+		}
+	}
+
+	r.collectors = append(r.collectors, c)
+}
+
+// family holds what the series of a metric have in common.
+type family struct {
+	help   string
+	labels []string
+	mu     sync.Mutex // Guards the values of the metric.
+	name   string
+	kind   string
+}
+
+func newFamily(name, help, kind string, labels []string) family {
+	return family{
+		help:   help,
+		labels: labels,
+		mu:     sync.Mutex{},
+		name:   name,
+		kind:   kind,
+	}
+}
+
+// metricName returns the name of the metric.
+func (f *family) metricName() string {
+	return f.name
+}
+
+// key identifies a series by its label values. It panics if the number of values does not match the label names,
+// like regexp.MustCompile this is a programming error.
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic("metrics: " + f.name + " expects " + strconv.Itoa(len(f.labels)) + " label values, got " + strconv.Itoa(len(values)))
+	}
+
+	return strings.Join(values, "\xff")
+}
+
+// writeHeader writes the HELP and TYPE lines.
+func (f *family) writeHeader(w *bufio.Writer) {
+	w.WriteString("# HELP " + f.name + " " + strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(f.help) + "\n")
+	w.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+}
+
+// writeSample writes a single sample, extra is an additional label such as the le of histogram buckets.
+func (f *family) writeSample(w *bufio.Writer, suffix, key string, value float64, extra ...string) {
+	w.WriteString(f.name + suffix)
+
+	pairs := slices.Concat(labelPairs(f.labels, key), extra)
+	if len(pairs) > 0 {
+		w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+// labelPairs returns the name="value" pairs of a series key.
+func labelPairs(labels []string, key string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	values := strings.Split(key, "\xff")
+	pairs := make([]string, len(labels))
+
+	for i, label := range labels {
+		pairs[i] = label + "=" + quote(values[i])
+	}
+
+	return pairs
+}
+
+// quote escapes a label value.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// formatFloat formats a sample value, infinities are written as +Inf and -Inf.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// Counter is a value that only goes up, such as a number of requests.
+type Counter struct {
+	family
+	values map[string]float64
+}
+
+// Add adds v to the series with the given label values. Negative values are ignored.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] += v
+}
+
+// Inc adds one to the series with the given label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w)
+
+	for _, key := range slices.Sorted(maps.Keys(c.values)) {
+		c.writeSample(w, "", key, c.values[key])
+	}
+}
+
+// Histogram counts observations, such as latencies, in buckets.
+type Histogram struct {
+	family
+	buckets []float64
+	values  map[string]*histogramValue
+}
+
+// histogramValue holds the observations of a single series.
+type histogramValue struct {
+	counts []uint64 // Observations per bucket, not cumulative.
+	count  uint64
+	sum    float64
+}
+
+// Observe adds v to the series with the given label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &histogramValue{counts: make([]uint64, len(h.buckets)), count: 0, sum: 0}
+		h.values[key] = hv
+	}
+
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+
+	hv.count++
+	hv.sum += v
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w)
+
+	for _, key := range slices.Sorted(maps.Keys(h.values)) {
+		hv := h.values[key]
+		cumulative := uint64(0)
+
+		for i, bound := range h.buckets {
+			cumulative += hv.counts[i]
+			h.writeSample(w, "_bucket", key, float64(cumulative), "le="+quote(formatFloat(bound)))
+		}
+
+		h.writeSample(w, "_bucket", key, float64(hv.count), `le="+Inf"`)
+		h.writeSample(w, "_sum", key, hv.sum)
+		h.writeSample(w, "_count", key, float64(hv.count))
+	}
+}
+
+// gaugeFunc is a gauge collected when the metrics are written.
+type gaugeFunc struct {
+	family
+	collect func(set func(value float64, labelValues ...string))
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	values := make(map[string]float64)
+
+	g.collect(func(value float64, labelValues ...string) {
+		values[g.key(labelValues)] = value
+	})
+
+	g.writeHeader(w)
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		g.writeSample(w, "", key, values[key])
+	}
+}
@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luca-arch/code-drills/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+
+	requests := reg.Counter("requests_total", "Requests served.\nBy route.", "route", "status")
+	requests.Inc("GET /balance", "200")
+	requests.Inc("GET /balance", "200")
+	requests.Add(3, "GET /tenants", "502")
+	requests.Add(-1, "GET /tenants", "502") // Ignored.
+	requests.Inc(`say "hi"\`, "404")
+
+	duration := reg.Histogram("duration_seconds", "Request duration.", []float64{1, .5})
+	duration.Observe(.25)
+	duration.Observe(.5)
+	duration.Observe(2)
+
+	reg.GaugeFunc("budget", "Remaining budget.", []string{"limit"}, func(set func(float64, ...string)) {
+		set(59, "minute")
+		set(4999, "day")
+	})
+
+	reg.Counter("empty_total", "No samples.")
+
+	var out bytes.Buffer
+
+	assert.NoError(t, reg.Write(&out))
+	assert.Equal(t, `# HELP requests_total Requests served.\nBy route.
+# TYPE requests_total counter
+requests_total{route="GET /balance",status="200"} 2
+requests_total{route="GET /tenants",status="502"} 3
+requests_total{route="say \"hi\"\\",status="404"} 1
+# HELP duration_seconds Request duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{le="0.5"} 2
+duration_seconds_bucket{le="1"} 2
+duration_seconds_bucket{le="+Inf"} 3
+duration_seconds_sum 2.75
+duration_seconds_count 3
+# HELP budget Remaining budget.
+# TYPE budget gauge
+budget{limit="day"} 4999
+budget{limit="minute"} 59
+# HELP empty_total No samples.
+# TYPE empty_total counter
+`, out.String())
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+	reg.Counter("requests_total", "Requests served.").Inc()
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "requests_total 1\n")
+}
+
+func TestLabelValuesMismatch(t *testing.T) {
+	t.Parallel()
+
+	requests := metrics.NewRegistry().Counter("requests_total", "Requests served.", "route")
+
+	assert.Panics(t, func() { requests.Inc() })
+	assert.Panics(t, func() { requests.Inc("GET /balance", "200") })
+}
+
+func TestDuplicateName(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+	reg.Counter("requests_total", "Requests served.", "route")
+
+	assert.Panics(t, func() { reg.Counter("requests_total", "Requests served.", "route") })
+	assert.Panics(t, func() { reg.Histogram("requests_total", "Requests served.", metrics.DefaultBuckets) })
+	assert.Panics(t, func() { reg.GaugeFunc("requests_total", "Requests served.", nil, func(func(float64, ...string)) {}) })
+	assert.NotPanics(t, func() { reg.Counter("errors_total", "Errors returned.") })
+}
@@ -0,0 +1,238 @@
+package mockxero
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// AllRoutes is the route of the faults injected into every route without a fault of its own.
+const AllRoutes = "*"
+
+var ErrInvalidFault = errors.New("invalid fault") // Error returned for faults the mock server cannot inject.
+
+// Bodies of the responses with a broken payload, see Fault.
+const (
+	BodyKO        = "ko"        // 200 with a "Status": "ko" payload.
+	BodyMalformed = "malformed" // 200 with a body that is not JSON.
+	BodyTruncated = "truncated" // 200 with the fixture cut in half, then the connection is closed.
+)
+
+// Fault describes how the mock server misbehaves on a route, which is either "connections", "Organisation", the name of
+// a report such as "BalanceSheet", or AllRoutes.
+// Latency is added first, then the request fails in the first way that is set, in the order of the fields.
+type Fault struct {
+	Latency    Duration `json:"latency,omitempty"`    // Delay before replying, e.g. "1.5s".
+	Drop       bool     `json:"drop,omitempty"`       // Close the connection without replying.
+	Status     int      `json:"status,omitempty"`     // Reply with 429 or a 5xx status.
+	RetryAfter int      `json:"retryAfter,omitempty"` // Seconds of the Retry-After header, sent with Status.
+	Body       string   `json:"body,omitempty"`       // Reply with a broken payload: BodyKO, BodyMalformed or BodyTruncated.
+	Times      int      `json:"times,omitempty"`      // Number of requests the fault applies to, zero until it is cleared.
+}
+
+// Validate returns ErrInvalidFault if the fault cannot be injected.
+func (f Fault) Validate() error {
+	switch {
+	case f.Latency < 0:
+		return errors.Join(ErrInvalidFault, errors.New("latency cannot be negative")) //nolint:err113 // Detail of ErrInvalidFault
+	case f.Status != 0 && f.Status != http.StatusTooManyRequests && (f.Status < 500 || f.Status > 599):
+		return errors.Join(ErrInvalidFault, errors.New("status must be 429 or 5xx")) //nolint:err113 // Detail of ErrInvalidFault
+	case f.Body != "" && f.Body != BodyKO && f.Body != BodyMalformed && f.Body != BodyTruncated:
+		return errors.Join(ErrInvalidFault, errors.New("unknown body "+strconv.Quote(f.Body))) //nolint:err113 // Detail of ErrInvalidFault
+	case f.RetryAfter < 0 || f.Times < 0:
+		return errors.Join(ErrInvalidFault, errors.New("retryAfter and times cannot be negative")) //nolint:err113 // Detail of ErrInvalidFault
+	default:
+		return nil
+	}
+}
+
+// Duration is a time.Duration encoded in JSON as a string, such as "250ms".
+type Duration time.Duration
+
+// MarshalJSON satisfies the json.Marshaler interface.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String()) //nolint:wrapcheck // Strings are always encoded
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.Join(ErrInvalidFault, err)
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return errors.Join(ErrInvalidFault, err)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// LoadScenario reads faults by route from a JSON object, such as {"BalanceSheet": {"status": 503, "times": 2}}.
+func LoadScenario(r io.Reader) (map[string]Fault, error) {
+	var faults map[string]Fault
+
+	if err := json.NewDecoder(r).Decode(&faults); err != nil {
+		return nil, errors.Join(ErrInvalidFault, err)
+	}
+
+	for route, fault := range faults {
+		if err := fault.Validate(); err != nil {
+			return nil, errors.Join(err, errors.New("route "+route)) //nolint:err113 // Detail of ErrInvalidFault
+		}
+	}
+
+	return faults, nil
+}
+
+// takeFault returns the fault of the route, if any, and counts the request against its Times.
+func (s *server) takeFault(route string) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.faults[route]; !ok {
+		route = AllRoutes
+	}
+
+	fault, ok := s.faults[route]
+	if !ok {
+		return Fault{}, false //nolint:exhaustruct // Zero value
+	}
+
+	if fault.Times > 0 {
+		if fault.Times--; fault.Times == 0 {
+			delete(s.faults, route)
+		} else {
+			s.faults[route] = fault
+		}
+	}
+
+	return fault, true
+}
+
+// inject returns a handler that injects the fault of the route, if any, before calling next.
+// The route is the name of the report, or the last segment of the path for the other endpoints.
+func (s *server) inject(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.PathValue("report")
+		if route == "" {
+			route = path.Base(r.URL.Path)
+		}
+
+		fault, ok := s.takeFault(route)
+		if !ok {
+			next(w, r)
+
+			return
+		}
+
+		s.logger.Info("Injecting fault", "route", route, "fault", fault)
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(time.Duration(fault.Latency)):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case fault.Drop:
+			panic(http.ErrAbortHandler) // Closes the connection without a response.
+		case fault.Status != 0:
+			if fault.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+			}
+
+			s.writeProblem(w, fault.Status, "Injected fault")
+		case fault.Body == BodyKO:
+			s.writeBody(w, []byte(`{"Status":"ko"}`))
+		case fault.Body == BodyMalformed:
+			s.writeBody(w, []byte(`{"Status":"OK","Reports":[{"ReportID":<html>`))
+		case fault.Body == BodyTruncated:
+			next(&truncatingWriter{ResponseWriter: w, written: 0}, r)
+			panic(http.ErrAbortHandler) // Closes the connection before the declared Content-Length is sent.
+		default:
+			next(w, r)
+		}
+	})
+}
+
+// truncatingWriter writes only the first half of the declared Content-Length.
+type truncatingWriter struct {
+	http.ResponseWriter
+
+	written int
+}
+
+func (tw *truncatingWriter) Write(p []byte) (int, error) {
+	length, _ := strconv.Atoi(tw.Header().Get("Content-Length"))
+
+	if keep := length/2 - tw.written; keep < len(p) {
+		p = p[:max(keep, 0)]
+	}
+
+	n, err := tw.ResponseWriter.Write(p)
+	tw.written += n
+
+	return n, err //nolint:wrapcheck // Errors of the underlying writer are returned as they are
+}
+
+// faultsHandler returns an HTTP handler that serves the admin endpoints of the faults:
+// - GET /_mock/faults lists the faults by route;
+// - PUT /_mock/faults/{route} sets the fault of a route from the request body;
+// - DELETE /_mock/faults/{route} clears the fault of a route;
+// - DELETE /_mock/faults clears every fault.
+func (s *server) faultsHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.PathValue("route")
+
+		s.logger.Debug("incoming admin request", "method", r.Method, "route", route)
+
+		switch r.Method {
+		case http.MethodPut:
+			var fault Fault
+
+			if err := json.NewDecoder(r.Body).Decode(&fault); err != nil {
+				http.Error(w, errors.Join(ErrInvalidFault, err).Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			if err := fault.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			s.WithFaults(map[string]Fault{route: fault})
+		case http.MethodDelete:
+			s.mu.Lock()
+
+			if route == "" {
+				clear(s.faults)
+			} else {
+				delete(s.faults, route)
+			}
+
+			s.mu.Unlock()
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(s.faults); err != nil {
+			s.logger.Warn("Could not marshal into response", "err", err)
+		}
+	})
+}
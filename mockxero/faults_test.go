@@ -0,0 +1,175 @@
+package mockxero_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/mockxero"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaults(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		faults map[string]mockxero.Fault
+		err    error
+	}{
+		"no fault on the route": {
+			faults: map[string]mockxero.Fault{"TrialBalance": {Status: http.StatusServiceUnavailable}},
+			err:    nil,
+		},
+		"latency": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Latency: mockxero.Duration(10 * time.Millisecond)}},
+			err:    nil,
+		},
+		"latency over the deadline": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Latency: mockxero.Duration(time.Minute)}},
+			err:    context.DeadlineExceeded,
+		},
+		"status 503": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Status: http.StatusServiceUnavailable}},
+			err:    xero.ErrXeroDown,
+		},
+		"status 429": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Status: http.StatusTooManyRequests, RetryAfter: 7}},
+			err:    xero.ErrTooManyRequests,
+		},
+		"ko body": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Body: mockxero.BodyKO}},
+			err:    xero.ErrBrokenResponse,
+		},
+		"malformed body": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Body: mockxero.BodyMalformed}},
+			err:    xero.ErrInvalidResponse,
+		},
+		"truncated body": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Body: mockxero.BodyTruncated}},
+			err:    xero.ErrInvalidResponse,
+		},
+		"dropped connection": {
+			faults: map[string]mockxero.Fault{"BalanceSheet": {Drop: true}},
+			err:    xero.ErrRequestFailure,
+		},
+		"all routes": {
+			faults: map[string]mockxero.Fault{mockxero.AllRoutes: {Status: http.StatusBadGateway}},
+			err:    xero.ErrXeroDown,
+		},
+		"route takes precedence over all routes": {
+			faults: map[string]mockxero.Fault{mockxero.AllRoutes: {Status: http.StatusBadGateway}, "BalanceSheet": {Body: mockxero.BodyKO}},
+			err:    xero.ErrBrokenResponse,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(mockxero.HTTPServer(nil).WithFaults(test.faults).Mux())
+			t.Cleanup(srv.Close)
+
+			ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+			defer cancel()
+
+			rr, err := xero.HTTPClient(nil).
+				WithBaseURL(srv.URL).
+				WithTenant(tenantID).
+				BalanceSheet(ctx, xero.BalanceSheetParams{})
+
+			if test.err == nil {
+				assert.NoError(t, err)
+				assert.Len(t, rr.Reports, 1)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, test.err)
+
+			var apiErr *xero.APIError
+
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+				assert.Equal(t, 7*time.Second, apiErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestFaultTimes(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(mockxero.HTTPServer(nil).
+		WithFaults(map[string]mockxero.Fault{"BalanceSheet": {Status: http.StatusInternalServerError, Times: 2}}).
+		Mux())
+	t.Cleanup(srv.Close)
+
+	client := xero.HTTPClient(nil).
+		WithBaseURL(srv.URL).
+		WithTenant(tenantID).
+		WithRetryPolicy(xero.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	// The retries outlast the fault.
+	rr, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.Len(t, rr.Reports, 1)
+}
+
+func TestFaultsAdmin(t *testing.T) {
+	t.Parallel()
+
+	server := mockxero.HTTPServer(nil).Mux()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+		return rec
+	}
+
+	rec := do(http.MethodPut, "/_mock/faults/BalanceSheet", `{"latency":"250ms","status":503,"times":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"BalanceSheet":{"latency":"250ms","status":503,"times":2}}`, rec.Body.String())
+
+	rec = do(http.MethodPut, "/_mock/faults/*", `{"body":"ko"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodGet, "/_mock/faults", "")
+	assert.JSONEq(t, `{"*":{"body":"ko"},"BalanceSheet":{"latency":"250ms","status":503,"times":2}}`, rec.Body.String())
+
+	rec = do(http.MethodDelete, "/_mock/faults/BalanceSheet", "")
+	assert.JSONEq(t, `{"*":{"body":"ko"}}`, rec.Body.String())
+
+	rec = do(http.MethodDelete, "/_mock/faults", "")
+	assert.JSONEq(t, `{}`, rec.Body.String())
+
+	for _, body := range []string{`{"status":404}`, `{"body":"empty"}`, `{"latency":"soon"}`, `{"times":-1}`, `[]`} {
+		rec = do(http.MethodPut, "/_mock/faults/BalanceSheet", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		assert.Contains(t, rec.Body.String(), mockxero.ErrInvalidFault.Error(), body)
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	t.Parallel()
+
+	faults, err := mockxero.LoadScenario(strings.NewReader(`{
+		"BalanceSheet": {"status": 429, "retryAfter": 30, "times": 1},
+		"*": {"latency": "2s"}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]mockxero.Fault{
+		"BalanceSheet":     {Status: http.StatusTooManyRequests, RetryAfter: 30, Times: 1},
+		mockxero.AllRoutes: {Latency: mockxero.Duration(2 * time.Second)},
+	}, faults)
+
+	_, err = mockxero.LoadScenario(strings.NewReader(`{"BalanceSheet": {"status": 200}}`))
+	assert.ErrorIs(t, err, mockxero.ErrInvalidFault)
+
+	_, err = mockxero.LoadScenario(strings.NewReader(`not JSON`))
+	assert.ErrorIs(t, err, mockxero.ErrInvalidFault)
+}
@@ -0,0 +1,112 @@
+package mockxero
+
+import (
+	"embed"
+	"io/fs"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed fixtures
+var embedded embed.FS
+
+// maxSelectors caps the parameters a fixture is selected by, as every combination of them is looked up.
+const maxSelectors = 6
+
+// selectors are the query parameters of the Xero reports that select a fixture, any other parameter is ignored.
+var selectors = []string{ //nolint:gochecknoglobals // Read-only list of parameters
+	"date",
+	"fromDate",
+	"periods",
+	"toDate",
+	"trackingCategoryID",
+	"trackingCategoryID2",
+	"trackingOptionID",
+	"trackingOptionID1",
+	"trackingOptionID2",
+}
+
+// Fixtures returns the fixtures embedded in the package.
+//
+// The layout is the same for any fixtures served by the mock server:
+// - connections.json holds the response of GET /connections, and the tenants the reports are served to;
+// - organisation.json holds the response of GET /api.xro/2.0/Organisation;
+// - {report}/default.json holds the response of GET /api.xro/2.0/Reports/{report};
+// - {report}/{param}-{value}.json holds the response for the given value of a parameter, such as
+// BalanceSheet/date-2024-07-31.json. Several parameters are joined by underscores in alphabetical order, such as
+// BalanceSheet/date-2024-07-31_periods-2.json.
+//
+// The fixture matching the most parameters of the request is served, only the first 6 parameters in alphabetical
+// order are taken into account.
+func Fixtures() fs.FS { //nolint:ireturn // Sub-trees of embed.FS are only known by interface
+	fsys, err := fs.Sub(embedded, "fixtures")
+	if err != nil {
+		panic(err) // The directory is embedded at build time.
+	}
+
+	return fsys
+}
+
+// selectFixture returns the name of the fixture matching the most parameters of the query.
+// Fixtures of a combination of parameters take precedence over single parameters, the default one comes last.
+func selectFixture(fsys fs.FS, report string, query url.Values) (string, bool) {
+	if !fs.ValidPath(report) || strings.Contains(report, "/") {
+		return "", false
+	}
+
+	var params []string
+
+	for name := range query {
+		if value := query.Get(name); value != "" && slices.Contains(selectors, name) {
+			params = append(params, name+"-"+value)
+		}
+	}
+
+	slices.Sort(params)
+
+	if len(params) > maxSelectors {
+		params = params[:maxSelectors]
+	}
+
+	for _, candidate := range combinations(params) {
+		name := path.Join(report, strings.Join(candidate, "_")+".json")
+		if len(candidate) == 0 {
+			name = path.Join(report, "default.json")
+		}
+
+		if _, err := fs.Stat(fsys, name); err == nil {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// combinations returns every subset of the sorted parameters, largest first, keeping them sorted.
+func combinations(params []string) [][]string {
+	subsets := [][]string{}
+
+	for mask := range 1 << len(params) {
+		subset := []string{}
+
+		for i, param := range params {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, param)
+			}
+		}
+
+		subsets = append(subsets, subset)
+	}
+
+	slices.SortStableFunc(subsets, func(a, b []string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+
+		return slices.Compare(a, b)
+	})
+
+	return subsets
+}
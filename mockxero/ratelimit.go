@@ -0,0 +1,104 @@
+package mockxero
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimits are the limits enforced by the mock server. Zero values disable a limit.
+// See https://developer.xero.com/documentation/guides/oauth2/limits/#api-rate-limits
+type RateLimits struct {
+	AppPerMinute int // Calls per minute, across all tenants.
+	PerDay       int // Calls per day, for each tenant.
+	PerMinute    int // Calls per minute, for each tenant.
+}
+
+// DefaultRateLimits returns Xero's rate limits.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		AppPerMinute: 10000, //nolint:mnd // Xero's limit
+		PerDay:       5000,  //nolint:mnd // Xero's limit
+		PerMinute:    60,    //nolint:mnd // Xero's limit
+	}
+}
+
+// window counts the calls of a fixed time window.
+type window struct {
+	calls int
+	start time.Time
+}
+
+// left returns the calls left in the window, starting a new one if it is over.
+func (w *window) left(now time.Time, size time.Duration, limit int) int {
+	if start := now.Truncate(size); !start.Equal(w.start) {
+		w.calls, w.start = 0, start
+	}
+
+	return limit - w.calls
+}
+
+// rateLimiter emulates Xero's rate limits, and the headers reporting them.
+type rateLimiter struct {
+	app    window
+	day    map[string]*window
+	limits RateLimits
+	minute map[string]*window
+	mu     sync.Mutex
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		app:    window{calls: 0, start: time.Time{}},
+		day:    make(map[string]*window),
+		limits: limits,
+		minute: make(map[string]*window),
+		mu:     sync.Mutex{},
+	}
+}
+
+// allow counts a call of the tenant and sets the X-*-Remaining headers.
+// If a limit is hit, it sets the Retry-After and X-Rate-Limit-Problem headers and returns false.
+func (rl *rateLimiter) allow(header http.Header, tenantID string, now time.Time) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.day[tenantID] == nil {
+		rl.day[tenantID] = &window{calls: 0, start: time.Time{}}
+		rl.minute[tenantID] = &window{calls: 0, start: time.Time{}}
+	}
+
+	checks := []struct {
+		header  string
+		limit   int
+		problem string
+		size    time.Duration
+		window  *window
+	}{
+		{header: "X-DayLimit-Remaining", limit: rl.limits.PerDay, problem: "day", size: 24 * time.Hour, window: rl.day[tenantID]},
+		{header: "X-MinLimit-Remaining", limit: rl.limits.PerMinute, problem: "minute", size: time.Minute, window: rl.minute[tenantID]},
+		{header: "X-AppMinLimit-Remaining", limit: rl.limits.AppPerMinute, problem: "appminute", size: time.Minute, window: &rl.app},
+	}
+
+	// Rejected calls are not counted.
+	for _, check := range checks {
+		if check.limit > 0 && check.window.left(now, check.size, check.limit) <= 0 {
+			retryAfter := check.window.start.Add(check.size).Sub(now)
+
+			header.Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			header.Set("X-Rate-Limit-Problem", check.problem)
+
+			return false
+		}
+	}
+
+	for _, check := range checks {
+		if check.limit > 0 {
+			check.window.calls++
+			header.Set(check.header, strconv.Itoa(check.limit-check.window.calls))
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,226 @@
+// Package mockxero provides a stand-in for the Xero API, serving report endpoints from fixture files.
+package mockxero
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// server defines a concrete type to serve mock Xero API requests.
+type server struct {
+	faults   map[string]Fault // Faults by route, see Fault.
+	fixtures fs.FS
+	limiter  *rateLimiter
+	logger   *slog.Logger
+	mu       sync.Mutex   // Guards faults.
+	requests atomic.Int64 // Served requests, used to generate correlation IDs.
+	tenants  map[string]bool
+}
+
+// HTTPServer returns a new mock Xero server, serving the embedded fixtures with Xero's default rate limits.
+func HTTPServer(logger *slog.Logger) *server { //nolint:revive // Ok to return this unexported struct
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	logger.Debug("initialising new mock Xero server")
+
+	s := &server{
+		faults:   make(map[string]Fault),
+		fixtures: nil,
+		limiter:  newRateLimiter(DefaultRateLimits()),
+		logger:   logger,
+		mu:       sync.Mutex{},
+		requests: atomic.Int64{},
+		tenants:  nil,
+	}
+
+	return s.WithFixtures(Fixtures())
+}
+
+// Mux returns a new server mux with the following routes:
+// - GET /connections
+// - GET /api.xro/2.0/Organisation
+// - GET /api.xro/2.0/Reports/{report}
+// - GET, DELETE /_mock/faults
+// - PUT, DELETE /_mock/faults/{route}
+//
+// The organisation and the reports require the Xero-tenant-id header to hold the ID of one of the connections.
+// Faults are injected into the Xero routes, see Fault.
+func (s *server) Mux() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /connections", s.inject(s.connectionsHandler()))
+	mux.Handle("GET /api.xro/2.0/Organisation", s.inject(s.organisationHandler()))
+	mux.Handle("GET /api.xro/2.0/Reports/{report}", s.inject(s.reportHandler()))
+	mux.Handle("GET /_mock/faults", s.faultsHandler())
+	mux.Handle("DELETE /_mock/faults", s.faultsHandler())
+	mux.Handle("PUT /_mock/faults/{route}", s.faultsHandler())
+	mux.Handle("DELETE /_mock/faults/{route}", s.faultsHandler())
+
+	return mux
+}
+
+// connectionsHandler returns an HTTP handler that serves the connections fixture.
+func (s *server) connectionsHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "path", r.URL.Path)
+
+		s.writeFixture(w, "connections.json")
+	})
+}
+
+// organisationHandler returns an HTTP handler that serves the organisation fixture.
+func (s *server) organisationHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "path", r.URL.Path)
+
+		if !s.allow(w, r) {
+			return
+		}
+
+		s.writeFixture(w, "organisation.json")
+	})
+}
+
+// reportHandler returns an HTTP handler that serves the fixture matching the report and its parameters.
+func (s *server) reportHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "path", r.URL.Path, "query", r.URL.RawQuery)
+
+		if !s.allow(w, r) {
+			return
+		}
+
+		name, ok := selectFixture(s.fixtures, r.PathValue("report"), r.URL.Query())
+		if !ok {
+			s.writeProblem(w, http.StatusNotFound, "The resource you're looking for cannot be found")
+
+			return
+		}
+
+		s.writeFixture(w, name)
+	})
+}
+
+// allow checks the tenant of the request and counts it against the tenant's rate limits.
+// It writes the error response and returns false if the request must not be served.
+func (s *server) allow(w http.ResponseWriter, r *http.Request) bool {
+	tenantID := r.Header.Get("Xero-tenant-id")
+	if !s.tenants[tenantID] {
+		s.writeProblem(w, http.StatusForbidden, "AuthenticationUnsuccessful")
+
+		return false
+	}
+
+	if !s.limiter.allow(w.Header(), tenantID, time.Now()) {
+		s.writeProblem(w, http.StatusTooManyRequests, "Rate limit exceeded")
+
+		return false
+	}
+
+	return true
+}
+
+// writeFixture copies the fixture into the response body.
+func (s *server) writeFixture(w http.ResponseWriter, name string) {
+	data, err := fs.ReadFile(s.fixtures, name)
+	if err != nil {
+		s.logger.Error("Could not read fixture", "fixture", name, "err", err)
+		s.writeProblem(w, http.StatusInternalServerError, "An error occurred in Xero")
+
+		return
+	}
+
+	s.logger.Debug("Serving fixture", "fixture", name)
+
+	s.writeBody(w, data)
+}
+
+// writeBody writes a 200 response with the given body.
+func (s *server) writeBody(w http.ResponseWriter, data []byte) {
+	s.writeHeaders(w)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+	if _, err := w.Write(data); err != nil {
+		s.logger.Warn("Could not write response", "err", err)
+	}
+}
+
+// writeProblem writes an error body in the format Xero uses for failures outside of the Accounting API payloads.
+func (s *server) writeProblem(w http.ResponseWriter, status int, detail string) {
+	s.writeHeaders(w)
+	w.WriteHeader(status)
+
+	problem := map[string]any{
+		"Title":  http.StatusText(status),
+		"Status": status,
+		"Detail": detail,
+	}
+
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		s.logger.Warn("Could not marshal into response", "err", err)
+	}
+}
+
+// writeHeaders sets the headers Xero sends with every response.
+func (s *server) writeHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Xero-Correlation-Id", "mock-"+strconv.FormatInt(s.requests.Add(1), 10))
+}
+
+// WithFaults sets the faults of the given routes, see Fault. Other routes keep their faults.
+func (s *server) WithFaults(faults map[string]Fault) *server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for route, fault := range faults {
+		s.faults[route] = fault
+	}
+
+	return s
+}
+
+// WithFixtures serves the fixtures from fsys, see Fixtures for the layout.
+// The tenants are read from connections.json, fixtures without it serve no tenant.
+func (s *server) WithFixtures(fsys fs.FS) *server {
+	s.fixtures = fsys
+	s.tenants = make(map[string]bool)
+
+	data, err := fs.ReadFile(fsys, "connections.json")
+	if err != nil {
+		s.logger.Warn("Could not read connections, no tenant will be served", "err", err)
+
+		return s
+	}
+
+	var connections []struct {
+		TenantID string `json:"tenantId"`
+	}
+
+	if err = json.Unmarshal(data, &connections); err != nil {
+		s.logger.Warn("Could not parse connections, no tenant will be served", "err", err)
+
+		return s
+	}
+
+	for _, connection := range connections {
+		s.tenants[connection.TenantID] = true
+	}
+
+	return s
+}
+
+// WithRateLimits sets the rate limits enforced on every tenant.
+func (s *server) WithRateLimits(limits RateLimits) *server {
+	s.limiter = newRateLimiter(limits)
+
+	return s
+}
@@ -0,0 +1,227 @@
+package mockxero_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/luca-arch/code-drills/mockxero"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+const tenantID = "70784a63-d24b-46a9-a4db-0e70a274b056" // From fixtures/connections.json.
+
+func TestReports(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path     string
+		tenantID string
+		status   int
+		titles   []string
+		columns  int
+	}{
+		"default": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "As at 31 August 2024"},
+			columns:  3,
+		},
+		"by date": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet?date=2024-07-31",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "As at 31 July 2024"},
+			columns:  3,
+		},
+		"by periods": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet?periods=2&standardLayout=true",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "As at 31 August 2024"},
+			columns:  4,
+		},
+		"by tracking option": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet?trackingOptionID1=9b2f1c7e-5d4a-4e3b-8f6a-2c1d0e9b8a70",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "Region: North", "As at 31 August 2024"},
+			columns:  3,
+		},
+		"unknown parameters": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet?date=2024-07-31&trackingColour=red&timeframe=MONTH",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "As at 31 July 2024"},
+			columns:  3,
+		},
+		"no fixture for the parameter": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet?date=2020-01-31",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Balance Sheet", "Demo Company (NZ)", "As at 31 August 2024"},
+			columns:  3,
+		},
+		"other report": {
+			path:     "/api.xro/2.0/Reports/TrialBalance?date=2024-07-31",
+			tenantID: tenantID,
+			status:   http.StatusOK,
+			titles:   []string{"Trial Balance", "Demo Company (NZ)", "As at 25 August 2024"},
+			columns:  5,
+		},
+		"error - unknown report": {
+			path:     "/api.xro/2.0/Reports/CashFlow",
+			tenantID: tenantID,
+			status:   http.StatusNotFound,
+		},
+		"error - unknown tenant": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet",
+			tenantID: "a3c9e7d1-2b4f-4e8a-8c6d-9f1b3e5a7c20",
+			status:   http.StatusForbidden,
+		},
+		"error - no tenant": {
+			path:     "/api.xro/2.0/Reports/BalanceSheet",
+			tenantID: "",
+			status:   http.StatusForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set("Xero-tenant-id", test.tenantID)
+
+			rec := httptest.NewRecorder()
+
+			mockxero.HTTPServer(nil).Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get("Xero-Correlation-Id"))
+
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var rr xero.ReportResponse
+
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rr))
+			assert.Equal(t, test.titles, rr.Reports[0].ReportTitles)
+			assert.Len(t, rr.Reports[0].Rows[0].Cells, test.columns)
+		})
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+
+	server := mockxero.HTTPServer(nil).
+		WithRateLimits(mockxero.RateLimits{AppPerMinute: 0, PerDay: 1000, PerMinute: 2})
+
+	get := func(tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api.xro/2.0/Reports/BalanceSheet", nil)
+		req.Header.Set("Xero-tenant-id", tenantID)
+
+		rec := httptest.NewRecorder()
+		server.Mux().ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := get(tenantID)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-MinLimit-Remaining"))
+	assert.Equal(t, "999", rec.Header().Get("X-DayLimit-Remaining"))
+	assert.Empty(t, rec.Header().Get("X-AppMinLimit-Remaining"))
+
+	rec = get(tenantID)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("X-MinLimit-Remaining"))
+
+	rec = get(tenantID)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "minute", rec.Header().Get("X-Rate-Limit-Problem"))
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Limits are per tenant.
+	rec = get("e0da6937-de07-4a14-adee-37abfac298ce")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-MinLimit-Remaining"))
+}
+
+func TestWithFixtures(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"connections.json":                  {Data: []byte(`[{"tenantId":"` + tenantID + `"}]`)},
+		"BalanceSheet/default.json":         {Data: []byte(`{"Status":"OK","Reports":[{"ReportID":"default"}]}`)},
+		"BalanceSheet/date-2024-07-31.json": {Data: []byte(`{"Status":"OK","Reports":[{"ReportID":"date"}]}`)},
+		"BalanceSheet/periods-3.json":       {Data: []byte(`{"Status":"OK","Reports":[{"ReportID":"periods"}]}`)},
+		"BalanceSheet/date-2024-07-31_periods-3.json": {
+			Data: []byte(`{"Status":"OK","Reports":[{"ReportID":"date and periods"}]}`),
+		},
+	}
+
+	tests := map[string]string{
+		"":                                     "default",
+		"?date=2024-07-31":                     "date",
+		"?periods=3":                           "periods",
+		"?periods=3&date=2024-07-31":           "date and periods",
+		"?periods=3&date=2024-06-30":           "periods",
+		"?periods=3&date=2024-07-31&timeframe": "date and periods",
+	}
+
+	for query, reportID := range tests {
+		t.Run(query, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/api.xro/2.0/Reports/BalanceSheet"+query, nil)
+			req.Header.Set("Xero-tenant-id", tenantID)
+
+			rec := httptest.NewRecorder()
+
+			mockxero.HTTPServer(nil).WithFixtures(fsys).Mux().ServeHTTP(rec, req)
+
+			var rr xero.ReportResponse
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rr))
+			assert.Equal(t, reportID, rr.Reports[0].ReportID)
+		})
+	}
+}
+
+// TestClient runs the Xero client against the mock server.
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(mockxero.HTTPServer(nil).Mux())
+	t.Cleanup(srv.Close)
+
+	client := xero.HTTPClient(nil).
+		WithBaseURL(srv.URL).
+		WithTenant(tenantID)
+
+	connections, err := client.Connections(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, connections, 2)
+
+	org, err := client.Organisation(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "NZD", org.BaseCurrency)
+
+	rr, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{Date: "2024-07-31"})
+	assert.NoError(t, err)
+	assert.Equal(t, "31 July 2024", rr.Reports[0].ReportDate)
+	assert.Empty(t, xero.ValidateBalanceSheet(rr.Reports[0]))
+	assert.Equal(t, 58, client.RateBudget(tenantID).Minute)
+
+	_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant("a3c9e7d1-2b4f-4e8a-8c6d-9f1b3e5a7c20"))
+	assert.ErrorIs(t, err, xero.ErrForbidden)
+}
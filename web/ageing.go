@@ -0,0 +1,211 @@
+package web
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+)
+
+const (
+	daysPerBucket = 30 // Width of an ageing bucket.
+	xeroCellDate  = "2006-01-02T15:04:05"
+)
+
+var (
+	errAgedLayout = errors.New("unexpected aged report layout") // The report lacks the expected columns.
+	errAmount     = errors.New("invalid amount")                // A cell does not contain a decimal amount.
+)
+
+// dueAmount returns the amount of a line in the given column, lines without one are an error.
+func dueAmount(line xero.Line, column int, currency string) (xero.Money, error) {
+	amount := line.Amount(column)
+	if amount == nil {
+		return xero.Money{}, errors.Join(errAmount, errors.New("no amount in row "+line.Label)) //nolint:err113 // Detail of errAmount
+	}
+
+	return xero.Money{Amount: *amount, Currency: currency}, nil
+}
+
+// agedBuckets contains outstanding amounts grouped by how many days they are overdue.
+type agedBuckets struct {
+	Current    xero.Money `json:"current"`    // Not due yet, or less than 30 days overdue.
+	Days30     xero.Money `json:"days30"`     // 30 to 59 days overdue.
+	Days60     xero.Money `json:"days60"`     // 60 to 89 days overdue.
+	Days90Plus xero.Money `json:"days90Plus"` // 90 days or more overdue.
+	Total      xero.Money `json:"total"`      // Sum of all buckets.
+}
+
+// zeroBuckets returns buckets with nothing outstanding.
+func zeroBuckets(currency string) agedBuckets {
+	zero := zeroAmount(currency)
+
+	return agedBuckets{Current: zero, Days30: zero, Days60: zero, Days90Plus: zero, Total: zero}
+}
+
+// zeroAmount returns 0.00, with the two digits Xero reports amounts with.
+func zeroAmount(currency string) xero.Money {
+	return xero.Money{Amount: xero.NewDecimal(0, 2), Currency: currency} //nolint:mnd // Cents have two digits
+}
+
+// add puts the amount in the bucket matching the number of days overdue.
+func (b *agedBuckets) add(due xero.Money, daysOverdue int) error {
+	var (
+		bucket *xero.Money
+		err    error
+	)
+
+	switch {
+	case daysOverdue < daysPerBucket:
+		bucket = &b.Current
+	case daysOverdue < 2*daysPerBucket:
+		bucket = &b.Days30
+	case daysOverdue < 3*daysPerBucket:
+		bucket = &b.Days60
+	default:
+		bucket = &b.Days90Plus
+	}
+
+	if *bucket, err = bucket.Add(due); err != nil {
+		return errors.Join(errAmount, err)
+	}
+
+	if b.Total, err = b.Total.Add(due); err != nil {
+		return errors.Join(errAmount, err)
+	}
+
+	return nil
+}
+
+// agedInvoice is an invoice or bill outstanding in an aged report.
+type agedInvoice struct {
+	agedBuckets
+
+	Date        string `json:"date"`
+	DueDate     string `json:"dueDate"`
+	DaysOverdue int    `json:"daysOverdue"`
+	Reference   string `json:"reference"`
+}
+
+// agedReport is the ageing view of an aged receivables or payables report.
+type agedReport struct {
+	AsAt           string        `json:"asAt"`
+	ContactID      string        `json:"contactID"`
+	OpeningBalance xero.Money    `json:"openingBalance"` // Outstanding before the report's invoices, in Total's oldest bucket.
+	Invoices       []agedInvoice `json:"invoices"`
+	Total          agedBuckets   `json:"total"`
+}
+
+// ageing groups the outstanding amounts of an aged report into buckets, counting days overdue up to asAt.
+// Amounts are in the given currency, the organisation's one. The opening balance has no due date, so it is counted in
+// the oldest bucket. Summary rows are skipped.
+func ageing(rr *xero.ReportResponse, contactID, currency string, asAt time.Time) (*agedReport, error) {
+	aged := &agedReport{
+		AsAt:           asAt.Format(time.DateOnly),
+		ContactID:      contactID,
+		OpeningBalance: zeroAmount(currency),
+		Invoices:       []agedInvoice{},
+		Total:          zeroBuckets(currency),
+	}
+
+	if rr == nil || len(rr.Reports) == 0 {
+		return aged, nil
+	}
+
+	tree := rr.Reports[0].Tree()
+	columns := agedColumns(tree.Periods)
+
+	for _, name := range []string{"Reference", "Due Date", "Due"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Join(errAgedLayout, errors.New("missing column "+name)) //nolint:err113 // Detail of errAgedLayout
+		}
+	}
+
+	for _, section := range tree.AllSections() {
+		for _, line := range section.Lines {
+			if line.Label == "Opening Balance" {
+				opening, err := dueAmount(line, columns["Due"], currency)
+				if err != nil {
+					return nil, err
+				}
+
+				if aged.OpeningBalance, err = aged.OpeningBalance.Add(opening); err != nil {
+					return nil, errors.Join(errAmount, err)
+				}
+
+				if err = aged.Total.add(opening, math.MaxInt); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			invoice, ok, err := agedLine(line, columns, currency, asAt)
+
+			switch {
+			case err != nil:
+				return nil, err
+			case ok:
+				aged.Invoices = append(aged.Invoices, invoice)
+
+				if err = aged.Total.add(invoice.Total, invoice.DaysOverdue); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return aged, nil
+}
+
+// agedColumns returns the index of each column of the report tree, the Date column being the line label.
+func agedColumns(periods []xero.Period) map[string]int {
+	columns := make(map[string]int)
+
+	for i, period := range periods {
+		if period.Label != "" {
+			columns[period.Label] = i
+		}
+	}
+
+	return columns
+}
+
+// agedLine reads an invoice line, it returns false for lines that are not invoices.
+func agedLine(line xero.Line, columns map[string]int, currency string, asAt time.Time) (agedInvoice, bool, error) {
+	var invoice agedInvoice
+
+	if len(line.Values) <= max(columns["Due Date"], columns["Due"], columns["Reference"]) {
+		return invoice, false, nil
+	}
+
+	dueDate, err := time.Parse(xeroCellDate, line.Values[columns["Due Date"]])
+	if err != nil {
+		return invoice, false, nil //nolint:nilerr // Not an invoice line
+	}
+
+	due, err := dueAmount(line, columns["Due"], currency)
+	if err != nil {
+		return invoice, false, err
+	}
+
+	date, _ := time.Parse(xeroCellDate, line.Label)
+
+	invoice.Date = date.Format(time.DateOnly)
+	invoice.DueDate = dueDate.Format(time.DateOnly)
+	invoice.DaysOverdue = max(int(asAt.Sub(dueDate).Hours()/24), 0) //nolint:mnd // Hours in a day
+	invoice.Reference = line.Values[columns["Reference"]]
+	invoice.agedBuckets = zeroBuckets(currency)
+
+	if err = invoice.add(due, invoice.DaysOverdue); err != nil {
+		return invoice, false, err
+	}
+
+	return invoice, true, nil
+}
+
+// endOfMonth returns the last day of the month of t, the date Xero ages reports at by default.
+func endOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location())
+}
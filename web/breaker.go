@@ -0,0 +1,248 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open") // Error returned without calling Xero while it is failing.
+
+// BreakerConfig configures the circuit breaker, see CircuitBreaker.
+type BreakerConfig struct {
+	FailureThreshold int           // Consecutive failures that open the circuit.
+	CoolOff          time.Duration // How long the circuit stays open before a trial call is let through.
+}
+
+// DefaultBreakerConfig returns the default circuit breaker configuration.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,                //nolint:mnd // Sensible default
+		CoolOff:          30 * time.Second, //nolint:mnd // Sensible default
+	}
+}
+
+// breakerState is the state of a circuit breaker.
+type breakerState int
+
+const (
+	stateClosed   breakerState = iota // Calls go through.
+	stateOpen                         // Calls fail fast with ErrCircuitOpen.
+	stateHalfOpen                     // A single trial call goes through, the others fail fast.
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateClosed:
+		return "closed"
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// openCircuitError is returned while the circuit is open. It matches ErrCircuitOpen through errors.Is.
+type openCircuitError struct {
+	retryAfter time.Duration // Time left before the next trial call.
+}
+
+// Error satisfies the error interface.
+func (e *openCircuitError) Error() string {
+	return ErrCircuitOpen.Error() + ", retry in " + e.retryAfter.Round(time.Second).String()
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *openCircuitError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// breaker is a circuit breaker around a Xero client.
+// The circuit opens after FailureThreshold consecutive calls fail because Xero is down or unreachable, then calls fail
+// fast with ErrCircuitOpen until CoolOff has elapsed. A single trial call is then let through: the circuit closes
+// if it succeeds, and opens again otherwise.
+type breaker struct {
+	client   xeroclient
+	config   BreakerConfig
+	failures int // Consecutive failures while closed.
+	logger   *slog.Logger
+	mu       sync.Mutex
+	openedAt time.Time
+	state    breakerState
+}
+
+// CircuitBreaker returns a Xero client that stops calling Xero while it is failing.
+func CircuitBreaker(logger *slog.Logger, apiClient xeroclient, config BreakerConfig) *breaker { //nolint:revive // Ok to return this unexported struct
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultBreakerConfig().FailureThreshold
+	}
+
+	return &breaker{
+		client:   apiClient,
+		config:   config,
+		failures: 0,
+		logger:   logger,
+		mu:       sync.Mutex{},
+		openedAt: time.Time{},
+		state:    stateClosed,
+	}
+}
+
+// AgedPayablesByContact calls the wrapped client, unless the circuit is open.
+func (b *breaker) AgedPayablesByContact(ctx context.Context, params xero.AgedReportParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.AgedPayablesByContact(ctx, params, opts...)
+	})
+}
+
+// AgedReceivablesByContact calls the wrapped client, unless the circuit is open.
+func (b *breaker) AgedReceivablesByContact(ctx context.Context, params xero.AgedReportParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.AgedReceivablesByContact(ctx, params, opts...)
+	})
+}
+
+// BalanceSheet calls the wrapped client, unless the circuit is open.
+func (b *breaker) BalanceSheet(ctx context.Context, params xero.BalanceSheetParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.BalanceSheet(ctx, params, opts...)
+	})
+}
+
+// BankSummary calls the wrapped client, unless the circuit is open.
+func (b *breaker) BankSummary(ctx context.Context, params xero.BankSummaryParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.BankSummary(ctx, params, opts...)
+	})
+}
+
+// Connections calls the wrapped client, unless the circuit is open.
+func (b *breaker) Connections(ctx context.Context) ([]xero.Connection, error) {
+	return guard(ctx, b, func() ([]xero.Connection, error) {
+		return b.client.Connections(ctx)
+	})
+}
+
+// ExecutiveSummary calls the wrapped client, unless the circuit is open.
+func (b *breaker) ExecutiveSummary(ctx context.Context, params xero.ExecutiveSummaryParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.ExecutiveSummary(ctx, params, opts...)
+	})
+}
+
+// Organisation calls the wrapped client, unless the circuit is open.
+func (b *breaker) Organisation(ctx context.Context, opts ...xero.CallOption) (*xero.Organisation, error) {
+	return guard(ctx, b, func() (*xero.Organisation, error) {
+		return b.client.Organisation(ctx, opts...)
+	})
+}
+
+// ProfitAndLoss calls the wrapped client, unless the circuit is open.
+func (b *breaker) ProfitAndLoss(ctx context.Context, params xero.ProfitAndLossParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.ProfitAndLoss(ctx, params, opts...)
+	})
+}
+
+// TrialBalance calls the wrapped client, unless the circuit is open.
+func (b *breaker) TrialBalance(ctx context.Context, params xero.TrialBalanceParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	return guard(ctx, b, func() (*xero.ReportResponse, error) {
+		return b.client.TrialBalance(ctx, params, opts...)
+	})
+}
+
+// guard makes the call if the circuit lets it through, and records its outcome.
+func guard[T any](ctx context.Context, b *breaker, call func() (T, error)) (T, error) {
+	var zero T
+
+	if err := b.allow(); err != nil {
+		return zero, err
+	}
+
+	v, err := call()
+
+	b.record(ctx, err)
+
+	return v, err
+}
+
+// allow returns an *openCircuitError if the call must fail fast.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateClosed:
+		return nil
+	case stateOpen:
+		if left := b.config.CoolOff - time.Since(b.openedAt); left > 0 {
+			return &openCircuitError{retryAfter: left}
+		}
+
+		b.transition(stateHalfOpen)
+
+		return nil // The trial call.
+	default:
+		// A trial call is in progress.
+		return &openCircuitError{retryAfter: b.config.CoolOff}
+	}
+}
+
+// record updates the state with the outcome of a call.
+func (b *breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !reached(ctx, err):
+		// The trial call did not tell whether Xero is back, let the next one through.
+		if b.state == stateHalfOpen {
+			b.transition(stateOpen)
+		}
+	case errors.Is(err, xero.ErrXeroDown), errors.Is(err, xero.ErrRequestFailure):
+		b.failures++
+
+		if b.state == stateHalfOpen || b.failures >= b.config.FailureThreshold {
+			b.openedAt = time.Now()
+			b.transition(stateOpen)
+		}
+	default:
+		b.failures = 0
+
+		if b.state == stateHalfOpen {
+			b.transition(stateClosed)
+		}
+	}
+}
+
+// transition changes the state, the lock must be held.
+func (b *breaker) transition(state breakerState) {
+	if state == b.state {
+		return
+	}
+
+	b.logger.Warn("Circuit breaker state changed", "from", b.state, "to", state, "failures", b.failures)
+
+	if state == stateClosed {
+		b.failures = 0
+	}
+
+	b.state = state
+}
+
+// reached returns whether the call tells anything about Xero's health: calls rejected before reaching Xero, or
+// given up by the caller, do not.
+func reached(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, xero.ErrInvalidParam)
+}
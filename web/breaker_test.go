@@ -0,0 +1,137 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/web"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingClient counts the balance sheet calls that reach the mock client.
+type countingClient struct {
+	*mockClient
+
+	calls int
+}
+
+func (m *countingClient) BalanceSheet(ctx context.Context, params xero.BalanceSheetParams, opts ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.calls++
+
+	return m.mockClient.BalanceSheet(ctx, params, opts...)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.Join(xero.ErrXeroDown, errors.New("503")) //nolint:err113 // Test error
+
+	// Each step sets the error of the mock client, then calls BalanceSheet.
+	type step struct {
+		sleep   time.Duration
+		mockErr error
+		err     error
+		calls   int // Calls that reached the mock client so far.
+	}
+
+	tests := map[string]struct {
+		steps []step
+		logs  []string // State changes.
+	}{
+		"opens after the threshold": {steps: []step{
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 1},
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 2},
+			{mockErr: nil, err: web.ErrCircuitOpen, calls: 2},
+		}, logs: []string{"from=closed to=open"}},
+		"successes reset the count": {steps: []step{
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 1},
+			{mockErr: nil, err: nil, calls: 2},
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 3},
+			{mockErr: nil, err: nil, calls: 4},
+		}, logs: nil},
+		"other errors do not count": {steps: []step{
+			{mockErr: xero.ErrInvalidRequest, err: xero.ErrInvalidRequest, calls: 1},
+			{mockErr: xero.ErrNotFound, err: xero.ErrNotFound, calls: 2},
+			{mockErr: xero.ErrInvalidRequest, err: xero.ErrInvalidRequest, calls: 3},
+		}, logs: nil},
+		"closes after a successful trial call": {steps: []step{
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 1},
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 2},
+			{sleep: 60 * time.Millisecond, mockErr: nil, err: nil, calls: 3},
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 4},
+			{mockErr: nil, err: nil, calls: 5},
+		}, logs: []string{"from=closed to=open", "from=open to=half-open", "from=half-open to=closed"}},
+		"opens again after a failed trial call": {steps: []step{
+			{mockErr: errDown, err: xero.ErrXeroDown, calls: 1},
+			{mockErr: xero.ErrRequestFailure, err: xero.ErrRequestFailure, calls: 2},
+			{sleep: 60 * time.Millisecond, mockErr: errDown, err: xero.ErrXeroDown, calls: 3},
+			{mockErr: nil, err: web.ErrCircuitOpen, calls: 3},
+		}, logs: []string{"from=closed to=open", "from=open to=half-open", "from=half-open to=open"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+
+			client := &countingClient{mockClient: &mockClient{res: xeroStubReports(t)}, calls: 0}
+			cb := web.CircuitBreaker(slog.New(slog.NewTextHandler(&logs, nil)), client, web.BreakerConfig{
+				FailureThreshold: 2,
+				CoolOff:          50 * time.Millisecond,
+			})
+
+			for i, step := range test.steps {
+				time.Sleep(step.sleep)
+
+				client.err = step.mockErr
+
+				rr, err := cb.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+				if step.err == nil {
+					assert.NoError(t, err, "step %d", i)
+					assert.NotNil(t, rr, "step %d", i)
+				} else {
+					assert.ErrorIs(t, err, step.err, "step %d", i)
+				}
+
+				assert.Equal(t, step.calls, client.calls, "step %d", i)
+			}
+
+			assert.Equal(t, len(test.logs), strings.Count(logs.String(), "Circuit breaker state changed"))
+
+			for _, change := range test.logs {
+				assert.Contains(t, logs.String(), change)
+			}
+		})
+	}
+}
+
+func TestCircuitOpen(t *testing.T) {
+	t.Parallel()
+
+	client := &mockClient{err: xero.ErrXeroDown}
+	server := web.HTTPServer(nil, web.CircuitBreaker(nil, client, web.BreakerConfig{
+		FailureThreshold: 1,
+		CoolOff:          time.Minute,
+	}))
+
+	rec := httptest.NewRecorder()
+	server.Mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/balance", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.Mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/balance", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
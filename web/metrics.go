@@ -0,0 +1,72 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/luca-arch/code-drills/metrics"
+)
+
+// serverMetrics records the requests served by the web server.
+type serverMetrics struct {
+	duration *metrics.Histogram
+	registry *metrics.Registry
+	requests *metrics.Counter
+}
+
+// newServerMetrics registers the metrics of the web server:
+// - http_requests_total{route, status};
+// - http_request_duration_seconds{route, status}.
+//
+// Routes are the patterns of the server mux, such as "GET /balance", or "unmatched" for requests no route matched.
+func newServerMetrics(reg *metrics.Registry) *serverMetrics {
+	return &serverMetrics{
+		duration: reg.Histogram("http_request_duration_seconds", "Duration of the HTTP requests served.",
+			metrics.DefaultBuckets, "route", "status"),
+		registry: reg,
+		requests: reg.Counter("http_requests_total", "HTTP requests served.", "route", "status"),
+	}
+}
+
+// instrument returns an HTTP handler that records every request served by the mux.
+func (m *serverMetrics) instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		mux.ServeHTTP(sw, r)
+
+		// The mux sets the pattern on the request it was given.
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := strconv.Itoa(sw.status)
+
+		m.requests.Inc(route, status)
+		m.duration.Observe(time.Since(start).Seconds(), route, status)
+	})
+}
+
+// statusWriter keeps track of the status code written to the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the original response writer, for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
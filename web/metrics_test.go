@@ -0,0 +1,66 @@
+package web_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/luca-arch/code-drills/metrics"
+	"github.com/luca-arch/code-drills/web"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	client := &mockClient{res: xeroStubReports(t)}
+	mux := web.HTTPServer(nil, client).WithMetrics(metrics.NewRegistry()).Mux()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec
+	}
+
+	get("/balance")
+	get("/balance?periods=2")
+	get("/balance?periods=12")
+	get("/tenants/70784a63-d24b-46a9-a4db-0e70a274b056/balance")
+	get("/nowhere")
+
+	client.err = xero.ErrXeroDown
+
+	get("/balance")
+
+	rec := get("/metrics")
+	body, _ := io.ReadAll(rec.Body)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	for _, line := range []string{
+		`http_requests_total{route="GET /balance",status="200"} 2`,
+		`http_requests_total{route="GET /balance",status="400"} 1`,
+		`http_requests_total{route="GET /balance",status="504"} 1`,
+		`http_requests_total{route="GET /tenants/{tenantID}/balance",status="200"} 1`,
+		`http_requests_total{route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{route="GET /balance",status="200"} 2`,
+	} {
+		assert.Contains(t, string(body), line+"\n")
+	}
+
+	// The scrape itself is recorded once it is served.
+	assert.NotContains(t, string(body), `route="GET /metrics"`)
+	assert.Contains(t, get("/metrics").Body.String(), `http_requests_total{route="GET /metrics",status="200"} 1`)
+}
+
+func TestNoMetrics(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	web.HTTPServer(nil, &mockClient{}).Mux().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,213 @@
+package web
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+)
+
+// balanceSheetParams reads the GET "/balance" query parameters, as sent by the frontend's search form.
+// Unknown parameters are ignored, a *xero.ParamError naming the offending field is returned for invalid ones.
+func balanceSheetParams(query url.Values) (xero.BalanceSheetParams, error) {
+	var (
+		err    error
+		params xero.BalanceSheetParams
+	)
+
+	if params.Date, err = queryDate(query, "date"); err != nil {
+		return params, err
+	}
+
+	if params.Periods, err = queryInt(query, "periods"); err != nil {
+		return params, err
+	}
+
+	if params.PaymentsOnly, err = queryBool(query, "paymentsOnly"); err != nil {
+		return params, err
+	}
+
+	if params.StandardLayout, err = queryBool(query, "standardLayout"); err != nil {
+		return params, err
+	}
+
+	params.Timeframe = xero.Timeframe(query.Get("timeframe"))
+	params.TrackingOptionID1 = query.Get("trackingOptionID1")
+	params.TrackingOptionID2 = query.Get("trackingOptionID2")
+
+	return params, params.Validate()
+}
+
+// profitAndLossParams reads the GET "/profit-and-loss" query parameters.
+// Unknown parameters are ignored, a *xero.ParamError naming the offending field is returned for invalid ones.
+func profitAndLossParams(query url.Values) (xero.ProfitAndLossParams, error) {
+	var (
+		err    error
+		params xero.ProfitAndLossParams
+	)
+
+	if params.FromDate, err = queryDate(query, "fromDate"); err != nil {
+		return params, err
+	}
+
+	if params.ToDate, err = queryDate(query, "toDate"); err != nil {
+		return params, err
+	}
+
+	if params.Periods, err = queryInt(query, "periods"); err != nil {
+		return params, err
+	}
+
+	if params.PaymentsOnly, err = queryBool(query, "paymentsOnly"); err != nil {
+		return params, err
+	}
+
+	if params.StandardLayout, err = queryBool(query, "standardLayout"); err != nil {
+		return params, err
+	}
+
+	params.Timeframe = xero.Timeframe(query.Get("timeframe"))
+	params.TrackingCategoryID = query.Get("trackingCategoryID")
+	params.TrackingOptionID = query.Get("trackingOptionID")
+	params.TrackingCategoryID2 = query.Get("trackingCategoryID2")
+	params.TrackingOptionID2 = query.Get("trackingOptionID2")
+
+	return params, params.Validate()
+}
+
+// trialBalanceParams reads the GET "/trial-balance" query parameters.
+func trialBalanceParams(query url.Values) (xero.TrialBalanceParams, error) {
+	var (
+		err    error
+		params xero.TrialBalanceParams
+	)
+
+	if params.Date, err = queryDate(query, "date"); err != nil {
+		return params, err
+	}
+
+	if params.PaymentsOnly, err = queryBool(query, "paymentsOnly"); err != nil {
+		return params, err
+	}
+
+	return params, params.Validate()
+}
+
+// bankSummaryParams reads the GET "/bank-summary" query parameters.
+func bankSummaryParams(query url.Values) (xero.BankSummaryParams, error) {
+	var (
+		err    error
+		params xero.BankSummaryParams
+	)
+
+	if params.FromDate, err = queryDate(query, "fromDate"); err != nil {
+		return params, err
+	}
+
+	if params.ToDate, err = queryDate(query, "toDate"); err != nil {
+		return params, err
+	}
+
+	return params, params.Validate()
+}
+
+// executiveSummaryParams reads the GET "/executive-summary" query parameters.
+func executiveSummaryParams(query url.Values) (xero.ExecutiveSummaryParams, error) {
+	var (
+		err    error
+		params xero.ExecutiveSummaryParams
+	)
+
+	if params.Date, err = queryDate(query, "date"); err != nil {
+		return params, err
+	}
+
+	return params, params.Validate()
+}
+
+// agedReportParams reads the GET "/aged-receivables/{contactID}" and "/aged-payables/{contactID}" parameters.
+func agedReportParams(contactID string, query url.Values) (xero.AgedReportParams, error) {
+	var (
+		err    error
+		params xero.AgedReportParams
+	)
+
+	params.ContactID = contactID
+
+	if params.Date, err = queryDate(query, "date"); err != nil {
+		return params, err
+	}
+
+	if params.FromDate, err = queryDate(query, "fromDate"); err != nil {
+		return params, err
+	}
+
+	if params.ToDate, err = queryDate(query, "toDate"); err != nil {
+		return params, err
+	}
+
+	return params, params.Validate()
+}
+
+// queryBool parses a boolean query parameter, missing values are false.
+func queryBool(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &xero.ParamError{
+			Param:  name,
+			Value:  value,
+			Reason: "expected a boolean",
+		}
+	}
+
+	return b, nil
+}
+
+// queryDate parses either a plain date or an ISO 8601 timestamp, and returns it in the YYYY-MM-DD format expected by
+// Xero. Timestamps keep the date of their own offset, midnight in Sydney is the same day in Xero.
+func queryDate(query url.Values, name string) (string, error) {
+	value := query.Get(name)
+	if value == "" {
+		return "", nil
+	}
+
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		return value, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", &xero.ParamError{
+			Param:  name,
+			Value:  value,
+			Reason: "expected an ISO 8601 date",
+		}
+	}
+
+	return date.Format(time.DateOnly), nil
+}
+
+// queryInt parses an integer query parameter, missing values are zero.
+func queryInt(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &xero.ParamError{
+			Param:  name,
+			Value:  value,
+			Reason: "expected an integer",
+		}
+	}
+
+	return i, nil
+}
@@ -7,20 +7,34 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/luca-arch/code-drills/metrics"
 	"github.com/luca-arch/code-drills/xero"
 )
 
 // xeroclient defines an interface to make Xero API requests.
 type xeroclient interface {
-	BalanceSheet(context.Context) (*xero.ReportResponse, error)
+	AgedPayablesByContact(context.Context, xero.AgedReportParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	AgedReceivablesByContact(context.Context, xero.AgedReportParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	BalanceSheet(context.Context, xero.BalanceSheetParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	BankSummary(context.Context, xero.BankSummaryParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	Connections(context.Context) ([]xero.Connection, error)
+	ExecutiveSummary(context.Context, xero.ExecutiveSummaryParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	Organisation(context.Context, ...xero.CallOption) (*xero.Organisation, error)
+	ProfitAndLoss(context.Context, xero.ProfitAndLossParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	TrialBalance(context.Context, xero.TrialBalanceParams, ...xero.CallOption) (*xero.ReportResponse, error)
 }
 
 // server defines a concrete type to serve HTTP requests.
 type server struct {
-	client xeroclient
-	logger *slog.Logger
+	client  xeroclient
+	logger  *slog.Logger
+	metrics *serverMetrics
 }
 
 // HTTPServer returns a new HTTP server with default configuration.
@@ -32,44 +46,292 @@ func HTTPServer(logger *slog.Logger, apiClient xeroclient) *server { //nolint:re
 	logger.Debug("initialising new HTTP server")
 
 	return &server{
-		client: apiClient,
-		logger: logger,
+		client:  apiClient,
+		logger:  logger,
+		metrics: nil,
 	}
 }
 
 // Mux returns a new server mux with the following routes:
-// - GET /balance.
+// - GET /aged-payables/{contactID}
+// - GET /aged-receivables/{contactID}
+// - GET /balance
+// - GET /bank-summary
+// - GET /executive-summary
+// - GET /profit-and-loss
+// - GET /trial-balance
+// - GET /tenants
+// - GET /tenants/{tenantID}/... for each of the reports above.
+// - GET /metrics, if the server has metrics.
 func (s *server) Mux() http.Handler {
 	mux := &http.ServeMux{}
 
-	mux.Handle("GET /balance", s.listBalanceSheetHandler())
+	reports := map[string]http.HandlerFunc{
+		"/aged-payables/{contactID}":    s.agedReportHandler(s.client.AgedPayablesByContact),
+		"/aged-receivables/{contactID}": s.agedReportHandler(s.client.AgedReceivablesByContact),
+		"/balance":                      s.listBalanceSheetHandler(),
+		"/bank-summary":                 s.listBankSummaryHandler(),
+		"/executive-summary":            s.listExecutiveSummaryHandler(),
+		"/profit-and-loss":              s.listProfitAndLossHandler(),
+		"/trial-balance":                s.listTrialBalanceHandler(),
+	}
+
+	for path, handler := range reports {
+		mux.Handle("GET "+path, handler)
+		mux.Handle("GET /tenants/{tenantID}"+path, handler)
+	}
+
+	mux.Handle("GET /tenants", s.listTenantsHandler())
+
+	if s.metrics == nil {
+		return mux
+	}
+
+	mux.Handle("GET /metrics", s.metrics.registry.Handler())
 
-	return mux
+	return s.metrics.instrument(mux)
+}
+
+// agedReportHandler returns an HTTP handler that serves the ageing view of an aged report.
+func (s *server) agedReportHandler(fetch func(context.Context, xero.AgedReportParams, ...xero.CallOption) (*xero.ReportResponse, error)) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "client", r.Header.Get("User-Agent"), "path", r.URL.Path)
+
+		params, err := agedReportParams(r.PathValue("contactID"), r.URL.Query())
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		rr, err := fetch(tenantContext(r), params)
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		// Amounts are aged as at the report date, which defaults to the end of the month like in Xero.
+		// Dates have been validated already.
+		asAt := endOfMonth(time.Now().UTC())
+
+		switch {
+		case params.Date != "":
+			asAt, _ = time.Parse(time.DateOnly, params.Date)
+		case params.ToDate != "":
+			asAt, _ = time.Parse(time.DateOnly, params.ToDate)
+		}
+
+		// Xero reports carry no currency, amounts are in the organisation's one.
+		org, err := s.client.Organisation(tenantContext(r))
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		aged, err := ageing(rr, params.ContactID, org.BaseCurrency, asAt)
+		if err != nil {
+			s.logger.Warn("Could not age report", "err", err)
+			http.Error(w, "Xero API returned an unexpected report", http.StatusBadGateway)
+
+			return
+		}
+
+		writeStale(w, rr)
+		s.writeJSON(w, aged)
+	})
 }
 
 // listBalanceSheetHandler returns an HTTP handler that serves the GET "/balance" endpoint.
+// With ?validate=true the reports are checked by xero.ValidateBalanceSheet, and the findings added in a validation block.
 func (s *server) listBalanceSheetHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "client", r.Header.Get("User-Agent"), "path", r.URL.Path)
+
+		validate, err := queryBool(r.URL.Query(), "validate")
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		params, err := balanceSheetParams(r.URL.Query())
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		rr, err := s.client.BalanceSheet(tenantContext(r), params)
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		writeStale(w, rr)
+
+		if !validate {
+			s.writeJSON(w, rr)
+
+			return
+		}
+
+		s.writeJSON(w, validateBalanceSheet(rr))
+	})
+}
+
+// listBankSummaryHandler returns an HTTP handler that serves the GET "/bank-summary" endpoint.
+func (s *server) listBankSummaryHandler() http.HandlerFunc {
+	return s.reportHandler(func(ctx context.Context, query url.Values) (*xero.ReportResponse, error) {
+		params, err := bankSummaryParams(query)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.client.BankSummary(ctx, params) //nolint:wrapcheck // Errors are mapped by writeError
+	})
+}
+
+// listExecutiveSummaryHandler returns an HTTP handler that serves the GET "/executive-summary" endpoint.
+func (s *server) listExecutiveSummaryHandler() http.HandlerFunc {
+	return s.reportHandler(func(ctx context.Context, query url.Values) (*xero.ReportResponse, error) {
+		params, err := executiveSummaryParams(query)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.client.ExecutiveSummary(ctx, params) //nolint:wrapcheck // Errors are mapped by writeError
+	})
+}
+
+// listProfitAndLossHandler returns an HTTP handler that serves the GET "/profit-and-loss" endpoint.
+func (s *server) listProfitAndLossHandler() http.HandlerFunc {
+	return s.reportHandler(func(ctx context.Context, query url.Values) (*xero.ReportResponse, error) {
+		params, err := profitAndLossParams(query)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.client.ProfitAndLoss(ctx, params) //nolint:wrapcheck // Errors are mapped by writeError
+	})
+}
+
+// listTrialBalanceHandler returns an HTTP handler that serves the GET "/trial-balance" endpoint.
+func (s *server) listTrialBalanceHandler() http.HandlerFunc {
+	return s.reportHandler(func(ctx context.Context, query url.Values) (*xero.ReportResponse, error) {
+		params, err := trialBalanceParams(query)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.client.TrialBalance(ctx, params) //nolint:wrapcheck // Errors are mapped by writeError
+	})
+}
+
+// reportHandler returns an HTTP handler that parses the query string, fetches a report and writes it as JSON.
+func (s *server) reportHandler(fetch func(context.Context, url.Values) (*xero.ReportResponse, error)) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.logger.Debug("incoming HTTP request", "client", r.Header.Get("User-Agent"), "path", r.URL.Path)
+
+		rr, err := fetch(tenantContext(r), r.URL.Query())
+		if err != nil {
+			s.writeError(w, err)
+
+			return
+		}
+
+		writeStale(w, rr)
+		s.writeJSON(w, rr)
+	})
+}
+
+// listTenantsHandler returns an HTTP handler that serves the GET "/tenants" endpoint.
+func (s *server) listTenantsHandler() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		s.logger.Debug("incoming HTTP request", "client", r.Header.Get("User-Agent"))
 
-		rr, err := s.client.BalanceSheet(r.Context())
+		connections, err := s.client.Connections(r.Context())
+		if err != nil {
+			s.writeError(w, err)
 
-		switch {
-		case err == nil:
-			w.Header().Set("Content-Type", "application/json")
-
-			if err := json.NewEncoder(w).Encode(rr); err != nil {
-				s.logger.Warn("Could not marshal into response", "err", err)
-			}
-		case errors.Is(err, xero.ErrInvalidRequest):
-			// Fixme: this assumes an invalid GET parameter was passed from the client.
-			http.Error(w, "invalid parameter", http.StatusBadRequest)
-		case errors.Is(err, xero.ErrTooManyRequests):
-			http.Error(w, "enhance your calm!", http.StatusTooManyRequests)
-		case errors.Is(err, xero.ErrXeroDown):
-			http.Error(w, "Xero API not available at the moment", http.StatusGatewayTimeout)
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
+
+		s.writeJSON(w, connections)
 	})
 }
+
+// writeError maps Xero client errors to HTTP responses.
+// Xero API errors are logged along with their correlation ID, so that they can be reported to Xero support.
+func (s *server) writeError(w http.ResponseWriter, err error) {
+	var (
+		apiErr  *xero.APIError
+		openErr *openCircuitError
+	)
+
+	if errors.As(err, &apiErr) {
+		s.logger.Warn("Xero API error", "status", apiErr.StatusCode, "path", apiErr.Path, "correlationID", apiErr.CorrelationID, "err", err)
+	}
+
+	switch {
+	case errors.As(err, &openErr):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(openErr.retryAfter.Seconds()))))
+		http.Error(w, "Xero API not available at the moment, try again later", http.StatusServiceUnavailable)
+	case errors.Is(err, xero.ErrInvalidParam):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, xero.ErrUnauthorized), errors.Is(err, xero.ErrForbidden), errors.Is(err, xero.ErrTokenFailure):
+		// The server's credentials were rejected, there is nothing the caller can do about it.
+		http.Error(w, "upstream auth failed", http.StatusBadGateway)
+	case errors.Is(err, xero.ErrNotFound):
+		http.Error(w, "not found in Xero", http.StatusNotFound)
+	case errors.Is(err, xero.ErrInvalidRequest):
+		// Parameters are validated beforehand, so this means Xero rejected a request we considered valid.
+		http.Error(w, "Xero API rejected the request", http.StatusBadGateway)
+	case errors.Is(err, xero.ErrTooManyRequests):
+		http.Error(w, "enhance your calm!", http.StatusTooManyRequests)
+	case errors.Is(err, xero.ErrXeroDown):
+		http.Error(w, "Xero API not available at the moment", http.StatusGatewayTimeout)
+	default:
+		s.logger.Error("Unexpected error", "err", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// writeJSON encodes the value into the response body.
+func (s *server) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Warn("Could not marshal into response", "err", err)
+	}
+}
+
+// writeStale sets the Warning and Age headers of reports served from the cache past their TTL.
+// See RFC 7234, section 5.5.1.
+func writeStale(w http.ResponseWriter, rr *xero.ReportResponse) {
+	if !rr.Stale {
+		return
+	}
+
+	w.Header().Set("Warning", `110 - "Response is Stale"`)
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(rr.FetchedAt).Seconds())))
+}
+
+// tenantContext returns the request's context, carrying the tenant ID from the URL path if any.
+func tenantContext(r *http.Request) context.Context {
+	if tenantID := r.PathValue("tenantID"); tenantID != "" {
+		return xero.ContextWithTenant(r.Context(), tenantID)
+	}
+
+	return r.Context()
+}
+
+// WithMetrics registers the server's metrics in reg and serves them on GET /metrics, see newServerMetrics.
+// Metrics registered by others in reg, such as the Xero client's, are served too.
+func (s *server) WithMetrics(reg *metrics.Registry) *server {
+	s.metrics = newServerMetrics(reg)
+
+	return s
+}
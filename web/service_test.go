@@ -2,12 +2,15 @@ package web_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,11 +20,73 @@ import (
 )
 
 type mockClient struct {
-	err error
-	res *xero.ReportResponse
+	agedParams  xero.AgedReportParams
+	connections []xero.Connection
+	currency    string
+	err         error
+	params      xero.BalanceSheetParams
+	pnlParams   xero.ProfitAndLossParams
+	res         *xero.ReportResponse
+	summary     any // Parameters of the other reports.
+	tenantID    string
 }
 
-func (m *mockClient) BalanceSheet(context.Context) (*xero.ReportResponse, error) {
+func (m *mockClient) BalanceSheet(ctx context.Context, params xero.BalanceSheetParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.params = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) AgedPayablesByContact(ctx context.Context, params xero.AgedReportParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.agedParams = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) AgedReceivablesByContact(ctx context.Context, params xero.AgedReportParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.agedParams = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) BankSummary(ctx context.Context, params xero.BankSummaryParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.summary = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) ExecutiveSummary(ctx context.Context, params xero.ExecutiveSummaryParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.summary = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) TrialBalance(ctx context.Context, params xero.TrialBalanceParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.summary = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return m.res, m.err
+}
+
+func (m *mockClient) Connections(context.Context) ([]xero.Connection, error) {
+	return m.connections, m.err
+}
+
+func (m *mockClient) Organisation(ctx context.Context, _ ...xero.CallOption) (*xero.Organisation, error) {
+	m.tenantID = xero.TenantFromContext(ctx)
+
+	return &xero.Organisation{OrganisationID: "", Name: "", BaseCurrency: m.currency}, m.err
+}
+
+func (m *mockClient) ProfitAndLoss(ctx context.Context, params xero.ProfitAndLossParams, _ ...xero.CallOption) (*xero.ReportResponse, error) {
+	m.pnlParams = params
+	m.tenantID = xero.TenantFromContext(ctx)
+
 	return m.res, m.err
 }
 
@@ -33,6 +98,7 @@ func TestBalance(t *testing.T) {
 
 	type fields struct {
 		mockClient *mockClient
+		query      string
 	}
 
 	type wants struct {
@@ -56,14 +122,24 @@ func TestBalance(t *testing.T) {
 			},
 		},
 		"error - invalid GET parameters": {
+			fields{
+				mockClient: &mockClient{},
+				query:      "?periods=12",
+			},
+			wants{
+				body:   "invalid parameter \"periods\" (\"12\"): must be between 1 and 11\n",
+				status: http.StatusBadRequest,
+			},
+		},
+		"error - Xero rejected the request": {
 			fields{
 				mockClient: &mockClient{
 					err: xero.ErrInvalidRequest,
 				},
 			},
 			wants{
-				body:   "invalid parameter\n",
-				status: http.StatusBadRequest,
+				body:   "Xero API rejected the request\n",
+				status: http.StatusBadGateway,
 			},
 		},
 		"error - rate limit exceeded": {
@@ -95,7 +171,62 @@ func TestBalance(t *testing.T) {
 				},
 			},
 			wants{
-				body:   "something wrong\n",
+				body:   "Internal Server Error\n",
+				status: http.StatusInternalServerError,
+			},
+		},
+		"error - Xero rejected the access token": {
+			fields{
+				mockClient: &mockClient{
+					err: &xero.APIError{StatusCode: http.StatusUnauthorized, Path: "/api.xro/2.0/Reports/BalanceSheet"},
+				},
+			},
+			wants{
+				body:   "upstream auth failed\n",
+				status: http.StatusBadGateway,
+			},
+		},
+		"error - Xero denied access": {
+			fields{
+				mockClient: &mockClient{
+					err: xero.ErrForbidden,
+				},
+			},
+			wants{
+				body:   "upstream auth failed\n",
+				status: http.StatusBadGateway,
+			},
+		},
+		"error - no access token": {
+			fields{
+				mockClient: &mockClient{
+					err: xero.ErrTokenFailure,
+				},
+			},
+			wants{
+				body:   "upstream auth failed\n",
+				status: http.StatusBadGateway,
+			},
+		},
+		"error - not found": {
+			fields{
+				mockClient: &mockClient{
+					err: xero.ErrNotFound,
+				},
+			},
+			wants{
+				body:   "not found in Xero\n",
+				status: http.StatusNotFound,
+			},
+		},
+		"error - unexpected status": {
+			fields{
+				mockClient: &mockClient{
+					err: &xero.APIError{StatusCode: http.StatusConflict, Path: "/api.xro/2.0/Reports/BalanceSheet"},
+				},
+			},
+			wants{
+				body:   "Internal Server Error\n",
 				status: http.StatusInternalServerError,
 			},
 		},
@@ -111,7 +242,7 @@ func TestBalance(t *testing.T) {
 			t.Cleanup(testServer.Close)
 
 			//nolint:noctx // Ok when testing
-			res, err := http.Get(testServer.URL + "/balance")
+			res, err := http.Get(testServer.URL + "/balance" + test.fields.query)
 			assert.NoError(t, err)
 
 			body, err := io.ReadAll(res.Body)
@@ -127,6 +258,584 @@ func TestBalance(t *testing.T) {
 	}
 }
 
+func TestBalanceParams(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := map[string]struct {
+		query  string
+		params xero.BalanceSheetParams
+		status int
+	}{
+		"no parameters": {
+			query:  "",
+			params: xero.BalanceSheetParams{},
+			status: http.StatusOK,
+		},
+		"frontend search form": {
+			query: "?date=2024-08-25T10%3A30%3A00.000Z&periods=3&timeframe=QUARTER&standardLayout=true&paymentsOnly=false" +
+				"&trackingOptionID1=8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+			params: xero.BalanceSheetParams{
+				Date:              "2024-08-25",
+				Periods:           3,
+				Timeframe:         xero.TimeframeQuarter,
+				TrackingOptionID1: "8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+				StandardLayout:    true,
+			},
+			status: http.StatusOK,
+		},
+		"plain date": {
+			query:  "?date=2024-08-25",
+			params: xero.BalanceSheetParams{Date: "2024-08-25"},
+			status: http.StatusOK,
+		},
+		"timestamp east of UTC": {
+			query:  "?date=2024-07-31T00%3A00%3A00%2B10%3A00",
+			params: xero.BalanceSheetParams{Date: "2024-07-31"},
+			status: http.StatusOK,
+		},
+		"invalid date": {
+			query:  "?date=yesterday",
+			params: xero.BalanceSheetParams{},
+			status: http.StatusBadRequest,
+		},
+		"invalid periods": {
+			query:  "?periods=three",
+			params: xero.BalanceSheetParams{},
+			status: http.StatusBadRequest,
+		},
+		"invalid timeframe": {
+			query:  "?timeframe=WEEK",
+			params: xero.BalanceSheetParams{},
+			status: http.StatusBadRequest,
+		},
+		"invalid boolean": {
+			query:  "?paymentsOnly=maybe",
+			params: xero.BalanceSheetParams{},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &mockClient{res: xeroStubReports(t)}
+			server := web.HTTPServer(nopLogger, client)
+
+			req := httptest.NewRequest(http.MethodGet, "/balance"+test.query, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code, rec.Body.String())
+			assert.Equal(t, test.params, client.params)
+		})
+	}
+}
+
+func TestBalanceValidation(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var valid, broken xero.ReportResponse
+
+	report := fixture(t, "testdata/balance-sheet.json")
+
+	assert.NoError(t, json.Unmarshal([]byte(report), &valid))
+	assert.NoError(t, json.Unmarshal([]byte(report), &broken))
+
+	broken.Reports[0].Rows[3].Rows[0].Cells[1].Value = "1000.00" // Accounts Receivable
+
+	tests := map[string]struct {
+		client     *mockClient
+		path       string
+		status     int
+		validation string
+	}{
+		"not requested": {
+			client:     &mockClient{res: &valid},
+			path:       "/balance",
+			status:     http.StatusOK,
+			validation: "",
+		},
+		"valid report": {
+			client:     &mockClient{res: &valid},
+			path:       "/balance?validate=true",
+			status:     http.StatusOK,
+			validation: `{"valid":true,"findings":[]}`,
+		},
+		"inconsistent report": {
+			client: &mockClient{res: &broken},
+			path:   "/balance?validate=true&date=2024-08-31",
+			status: http.StatusOK,
+			validation: `{"valid":false,"findings":[{"check":"sectionTotal","section":"Current Assets","period":"31 Aug 2024",` +
+				`"expected":1250.00,"actual":1000.00,"message":"Total Current Assets is 1250.00 but the lines sum to 1000.00"}]}`,
+		},
+		"error - invalid flag": {
+			client: &mockClient{res: &valid},
+			path:   "/balance?validate=maybe",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, test.client)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+
+			if test.status != http.StatusOK {
+				return
+			}
+
+			var body map[string]json.RawMessage
+
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Contains(t, body, "Reports")
+
+			if test.validation == "" {
+				assert.NotContains(t, body, "validation")
+
+				return
+			}
+
+			assert.JSONEq(t, test.validation, string(body["validation"]))
+		})
+	}
+}
+
+func TestStaleReport(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	fresh := xeroStubReports(t)
+	fresh.FetchedAt = time.Now()
+
+	stale := xeroStubReports(t)
+	stale.FetchedAt = time.Now().Add(-90 * time.Second)
+	stale.Stale = true
+
+	tests := map[string]struct {
+		res     *xero.ReportResponse
+		path    string
+		status  int
+		warning string
+		age     string
+	}{
+		"fresh": {
+			res:     fresh,
+			path:    "/balance",
+			status:  http.StatusOK,
+			warning: "",
+			age:     "",
+		},
+		"stale balance sheet": {
+			res:     stale,
+			path:    "/balance",
+			status:  http.StatusOK,
+			warning: `110 - "Response is Stale"`,
+			age:     "90",
+		},
+		"stale trial balance": {
+			res:     stale,
+			path:    "/trial-balance",
+			status:  http.StatusOK,
+			warning: `110 - "Response is Stale"`,
+			age:     "90",
+		},
+		"stale report failing to age": {
+			res:     stale,
+			path:    "/aged-receivables/565acaa9-e7f3-4fbf-80c3-16b081ddae10",
+			status:  http.StatusBadGateway,
+			warning: "",
+			age:     "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, &mockClient{res: test.res})
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.warning, rec.Header().Get("Warning"))
+			assert.Equal(t, test.age, rec.Header().Get("Age"))
+		})
+	}
+}
+
+func TestProfitAndLoss(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := map[string]struct {
+		client *mockClient
+		path   string
+		body   string
+		params xero.ProfitAndLossParams
+		status int
+	}{
+		"success": {
+			client: &mockClient{res: xeroStubReports(t)},
+			path:   "/profit-and-loss?fromDate=2024-07-01&toDate=2024-07-31T12%3A00%3A00.000Z&timeframe=MONTH&periods=2",
+			body:   fixture(t, "testdata/get-balance.json"),
+			params: xero.ProfitAndLossParams{
+				FromDate:  "2024-07-01",
+				ToDate:    "2024-07-31",
+				Periods:   2,
+				Timeframe: xero.TimeframeMonth,
+			},
+			status: http.StatusOK,
+		},
+		"tenant": {
+			client: &mockClient{res: xeroStubReports(t)},
+			path:   "/tenants/70784a63-d24b-46a9-a4db-0e70a274b056/profit-and-loss",
+			body:   fixture(t, "testdata/get-balance.json"),
+			status: http.StatusOK,
+		},
+		"error - invalid GET parameters": {
+			client: &mockClient{},
+			path:   "/profit-and-loss?trackingOptionID=297c2dc5-cc47-4afd-8ec8-74990b8761e9",
+			body:   "invalid parameter \"trackingOptionID\" (\"297c2dc5-cc47-4afd-8ec8-74990b8761e9\"): requires trackingCategoryID\n",
+			status: http.StatusBadRequest,
+		},
+		"error - Xero rejected the request": {
+			client: &mockClient{err: xero.ErrInvalidRequest},
+			path:   "/profit-and-loss",
+			body:   "Xero API rejected the request\n",
+			status: http.StatusBadGateway,
+		},
+		"error - rate limit exceeded": {
+			client: &mockClient{err: xero.ErrTooManyRequests},
+			path:   "/profit-and-loss",
+			body:   "enhance your calm!\n",
+			status: http.StatusTooManyRequests,
+		},
+		"error - Xero API not reachable": {
+			client: &mockClient{err: xero.ErrXeroDown},
+			path:   "/profit-and-loss",
+			body:   "Xero API not available at the moment\n",
+			status: http.StatusGatewayTimeout,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, test.client)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.body, rec.Body.String())
+			assert.Equal(t, test.params, test.client.pnlParams)
+		})
+	}
+}
+
+func TestSummaryReports(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := map[string]struct {
+		client   *mockClient
+		path     string
+		params   any
+		status   int
+		tenantID string
+	}{
+		"trial balance": {
+			client: &mockClient{res: xeroStubReports(t)},
+			path:   "/trial-balance?date=2024-08-25&paymentsOnly=true",
+			params: xero.TrialBalanceParams{Date: "2024-08-25", PaymentsOnly: true},
+			status: http.StatusOK,
+		},
+		"trial balance - invalid date": {
+			client: &mockClient{},
+			path:   "/trial-balance?date=25-08-2024",
+			params: nil,
+			status: http.StatusBadRequest,
+		},
+		"bank summary": {
+			client:   &mockClient{res: xeroStubReports(t)},
+			path:     "/tenants/70784a63-d24b-46a9-a4db-0e70a274b056/bank-summary?fromDate=2024-08-01&toDate=2024-08-25",
+			params:   xero.BankSummaryParams{FromDate: "2024-08-01", ToDate: "2024-08-25"},
+			status:   http.StatusOK,
+			tenantID: "70784a63-d24b-46a9-a4db-0e70a274b056",
+		},
+		"bank summary - inverted range": {
+			client: &mockClient{},
+			path:   "/bank-summary?fromDate=2024-08-25&toDate=2024-08-01",
+			params: nil,
+			status: http.StatusBadRequest,
+		},
+		"executive summary": {
+			client: &mockClient{res: xeroStubReports(t)},
+			path:   "/executive-summary?date=2024-08-25",
+			params: xero.ExecutiveSummaryParams{Date: "2024-08-25"},
+			status: http.StatusOK,
+		},
+		"executive summary - Xero down": {
+			client: &mockClient{err: xero.ErrXeroDown},
+			path:   "/executive-summary",
+			params: xero.ExecutiveSummaryParams{},
+			status: http.StatusGatewayTimeout,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, test.client)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code, rec.Body.String())
+			assert.Equal(t, test.params, test.client.summary)
+			assert.Equal(t, test.tenantID, test.client.tenantID)
+
+			if test.status == http.StatusOK {
+				assert.Equal(t, fixture(t, "testdata/get-balance.json"), rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAgedReports(t *testing.T) {
+	t.Parallel()
+
+	const contactID = "565acaa9-e7f3-4fbf-80c3-16b081ddae10"
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	report := fixture(t, "testdata/aged-receivables.json")
+
+	var stub xero.ReportResponse
+
+	err := json.Unmarshal([]byte(report), &stub)
+	assert.NoError(t, err)
+
+	// Same report, with an opening balance.
+	var opening xero.ReportResponse
+
+	err = json.Unmarshal([]byte(strings.Replace(report, `"0.00"`, `"(350.25)"`, 1)), &opening)
+	assert.NoError(t, err)
+
+	broken := xero.ReportResponse{
+		Reports: []xero.Report{
+			{
+				Rows: []xero.Row{
+					{
+						RowType: "Header",
+						Cells:   []xero.Cell{{Value: "Date"}, {Value: "Amount"}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		client *mockClient
+		path   string
+		params xero.AgedReportParams
+		body   string
+		status int
+	}{
+		"receivables": {
+			client: &mockClient{res: &stub, currency: "NZD"},
+			path:   "/aged-receivables/" + contactID + "?date=2024-08-25",
+			params: xero.AgedReportParams{ContactID: contactID, Date: "2024-08-25"},
+			body: `{"asAt":"2024-08-25","contactID":"` + contactID + `","openingBalance":` + moneyJSON("0.00") + `,"invoices":[` +
+				`{` + agedJSON("1250.00", "0.00", "0.00", "0.00", "1250.00") + `,"date":"2024-08-10","dueDate":"2024-09-09","daysOverdue":0,"reference":"INV-0004"},` +
+				`{` + agedJSON("0.00", "980.00", "0.00", "0.00", "980.00") + `,"date":"2024-07-01","dueDate":"2024-07-20","daysOverdue":36,"reference":"INV-0003"},` +
+				`{` + agedJSON("0.00", "0.00", "430.50", "0.00", "430.50") + `,"date":"2024-05-01","dueDate":"2024-06-20","daysOverdue":66,"reference":"INV-0002"},` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "1500.00", "1500.00") + `,"date":"2024-03-01","dueDate":"2024-03-31","daysOverdue":147,"reference":"INV-0001"}],` +
+				`"total":{` + agedJSON("1250.00", "980.00", "430.50", "1500.00", "4160.50") + `}}` + "\n",
+			status: http.StatusOK,
+		},
+		"receivables - opening balance": {
+			client: &mockClient{res: &opening, currency: "NZD"},
+			path:   "/aged-receivables/" + contactID + "?date=2024-08-25",
+			params: xero.AgedReportParams{ContactID: contactID, Date: "2024-08-25"},
+			body: `{"asAt":"2024-08-25","contactID":"` + contactID + `","openingBalance":` + moneyJSON("-350.25") + `,"invoices":[` +
+				`{` + agedJSON("1250.00", "0.00", "0.00", "0.00", "1250.00") + `,"date":"2024-08-10","dueDate":"2024-09-09","daysOverdue":0,"reference":"INV-0004"},` +
+				`{` + agedJSON("0.00", "980.00", "0.00", "0.00", "980.00") + `,"date":"2024-07-01","dueDate":"2024-07-20","daysOverdue":36,"reference":"INV-0003"},` +
+				`{` + agedJSON("0.00", "0.00", "430.50", "0.00", "430.50") + `,"date":"2024-05-01","dueDate":"2024-06-20","daysOverdue":66,"reference":"INV-0002"},` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "1500.00", "1500.00") + `,"date":"2024-03-01","dueDate":"2024-03-31","daysOverdue":147,"reference":"INV-0001"}],` +
+				`"total":{` + agedJSON("1250.00", "980.00", "430.50", "1149.75", "3810.25") + `}}` + "\n",
+			status: http.StatusOK,
+		},
+		"payables - as at toDate": {
+			client: &mockClient{res: &stub, currency: "NZD"},
+			path:   "/tenants/70784a63-d24b-46a9-a4db-0e70a274b056/aged-payables/" + contactID + "?toDate=2024-12-31",
+			params: xero.AgedReportParams{ContactID: contactID, ToDate: "2024-12-31"},
+			body: `{"asAt":"2024-12-31","contactID":"` + contactID + `","openingBalance":` + moneyJSON("0.00") + `,"invoices":[` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "1250.00", "1250.00") + `,"date":"2024-08-10","dueDate":"2024-09-09","daysOverdue":113,"reference":"INV-0004"},` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "980.00", "980.00") + `,"date":"2024-07-01","dueDate":"2024-07-20","daysOverdue":164,"reference":"INV-0003"},` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "430.50", "430.50") + `,"date":"2024-05-01","dueDate":"2024-06-20","daysOverdue":194,"reference":"INV-0002"},` +
+				`{` + agedJSON("0.00", "0.00", "0.00", "1500.00", "1500.00") + `,"date":"2024-03-01","dueDate":"2024-03-31","daysOverdue":275,"reference":"INV-0001"}],` +
+				`"total":{` + agedJSON("0.00", "0.00", "0.00", "4160.50", "4160.50") + `}}` + "\n",
+			status: http.StatusOK,
+		},
+		"error - invalid contact": {
+			client: &mockClient{},
+			path:   "/aged-receivables/ridgeway",
+			body:   "invalid parameter \"contactID\" (\"ridgeway\"): expected a UUID\n",
+			status: http.StatusBadRequest,
+		},
+		"error - unexpected layout": {
+			client: &mockClient{res: &broken},
+			path:   "/aged-receivables/" + contactID,
+			params: xero.AgedReportParams{ContactID: contactID},
+			body:   "Xero API returned an unexpected report\n",
+			status: http.StatusBadGateway,
+		},
+		"error - rate limit exceeded": {
+			client: &mockClient{err: xero.ErrTooManyRequests},
+			path:   "/aged-payables/" + contactID,
+			params: xero.AgedReportParams{ContactID: contactID},
+			body:   "enhance your calm!\n",
+			status: http.StatusTooManyRequests,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, test.client)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.body, rec.Body.String())
+			assert.Equal(t, test.params, test.client.agedParams)
+		})
+	}
+}
+
+func TestAgedReportDefaultDate(t *testing.T) {
+	t.Parallel()
+
+	var stub xero.ReportResponse
+
+	assert.NoError(t, json.Unmarshal([]byte(fixture(t, "testdata/aged-receivables.json")), &stub))
+
+	req := httptest.NewRequest(http.MethodGet, "/aged-receivables/565acaa9-e7f3-4fbf-80c3-16b081ddae10", nil)
+	rec := httptest.NewRecorder()
+
+	web.HTTPServer(nil, &mockClient{res: &stub}).Mux().ServeHTTP(rec, req)
+
+	var aged struct {
+		AsAt string `json:"asAt"`
+	}
+
+	// Like Xero, reports are aged as at the end of the current month.
+	now := time.Now().UTC()
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &aged))
+	assert.Equal(t, time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC).Format(time.DateOnly), aged.AsAt)
+}
+
+func TestTenants(t *testing.T) {
+	t.Parallel()
+
+	nopLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := map[string]struct {
+		client   *mockClient
+		path     string
+		body     string
+		status   int
+		tenantID string
+	}{
+		"list tenants": {
+			client: &mockClient{
+				connections: []xero.Connection{
+					{
+						ID:         "e1eede29-f875-4a5d-8470-17f6a29a88b1",
+						TenantID:   "70784a63-d24b-46a9-a4db-0e70a274b056",
+						TenantType: "ORGANISATION",
+						TenantName: "Maple Florists Ltd",
+					},
+				},
+			},
+			path: "/tenants",
+			body: `[{"id":"e1eede29-f875-4a5d-8470-17f6a29a88b1","authEventId":"","tenantId":"70784a63-d24b-46a9-a4db-0e70a274b056",` +
+				`"tenantType":"ORGANISATION","tenantName":"Maple Florists Ltd","createdDateUtc":"","updatedDateUtc":""}]` + "\n",
+			status: http.StatusOK,
+		},
+		"list tenants - Xero down": {
+			client: &mockClient{err: xero.ErrXeroDown},
+			path:   "/tenants",
+			body:   "Xero API not available at the moment\n",
+			status: http.StatusGatewayTimeout,
+		},
+		"tenant balance": {
+			client:   &mockClient{res: xeroStubReports(t)},
+			path:     "/tenants/70784a63-d24b-46a9-a4db-0e70a274b056/balance",
+			body:     fixture(t, "testdata/get-balance.json"),
+			status:   http.StatusOK,
+			tenantID: "70784a63-d24b-46a9-a4db-0e70a274b056",
+		},
+		"default tenant balance": {
+			client:   &mockClient{res: xeroStubReports(t)},
+			path:     "/balance",
+			body:     fixture(t, "testdata/get-balance.json"),
+			status:   http.StatusOK,
+			tenantID: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := web.HTTPServer(nopLogger, test.client)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.Mux().ServeHTTP(rec, req)
+
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.body, rec.Body.String())
+			assert.Equal(t, test.tenantID, test.client.tenantID)
+		})
+	}
+}
+
 func fixture(t *testing.T, path string) string {
 	t.Helper()
 
@@ -138,6 +847,19 @@ func fixture(t *testing.T, path string) string {
 	return string(data)
 }
 
+// agedJSON returns the JSON fields of aged buckets.
+func agedJSON(current, days30, days60, days90Plus, total string) string {
+	return `"current":` + moneyJSON(current) + `,"days30":` + moneyJSON(days30) + `,"days60":` + moneyJSON(days60) +
+		`,"days90Plus":` + moneyJSON(days90Plus) + `,"total":` + moneyJSON(total)
+}
+
+// moneyJSON returns the JSON of an amount in NZD.
+func moneyJSON(amount string) string {
+	minorUnits, _ := strconv.Atoi(strings.Replace(amount, ".", "", 1))
+
+	return `{"amount":"` + amount + `","currency":"NZD","minorUnits":` + strconv.Itoa(minorUnits) + `}`
+}
+
 func xeroDTField(t *testing.T, unixSeconds int) xero.DateTimeField {
 	t.Helper()
 
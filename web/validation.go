@@ -0,0 +1,35 @@
+package web
+
+import (
+	"github.com/luca-arch/code-drills/xero"
+)
+
+// validatedReport is a Balance Sheet response followed by the result of its integrity checks.
+type validatedReport struct {
+	*xero.ReportResponse
+
+	Validation validation `json:"validation"`
+}
+
+// validation lists the inconsistencies found in the reports.
+type validation struct {
+	Valid    bool           `json:"valid"`
+	Findings []xero.Finding `json:"findings"`
+}
+
+// validateBalanceSheet checks every report of the response.
+func validateBalanceSheet(rr *xero.ReportResponse) validatedReport {
+	findings := []xero.Finding{}
+
+	for _, report := range rr.Reports {
+		findings = append(findings, xero.ValidateBalanceSheet(report)...)
+	}
+
+	return validatedReport{
+		ReportResponse: rr,
+		Validation: validation{
+			Valid:    len(findings) == 0,
+			Findings: findings,
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package xero
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxErrorBody = 64 << 10 // Error bodies larger than 64KiB are not parsed.
+
+var ErrUnexpectedStatus = errors.New("unexpected status in Xero response") // Error returned for status codes without a more specific error.
+
+// APIError is a non-200 response from the Xero API. It matches, through errors.Is, the sentinel error of its status code:
+// ErrInvalidRequest (400), ErrUnauthorized (401), ErrForbidden (403), ErrNotFound (404), ErrTooManyRequests (429),
+// ErrXeroDown (5xx) or ErrUnexpectedStatus.
+// See https://developer.xero.com/documentation/api/accounting/responsecodes
+type APIError struct {
+	StatusCode    int            `json:"-"`           // HTTP status code.
+	Path          string         `json:"-"`           // Path of the request, without the base URL and query string.
+	CorrelationID string         `json:"-"`           // Value of the Xero-Correlation-Id header, to be quoted when contacting Xero support.
+	RetryAfter    time.Duration  `json:"-"`           // Delay requested through the Retry-After header, zero if none.
+	ErrorNumber   int            `json:"ErrorNumber"` // Xero error number, e.g. 10 for validation errors.
+	Type          string         `json:"Type"`        // Xero exception type, e.g. ValidationException.
+	Message       string         `json:"Message"`     // Xero error message.
+	Elements      []ErrorElement `json:"Elements"`    // Elements that failed validation, if any.
+}
+
+// ErrorElement is an element of a request rejected by Xero.
+type ErrorElement struct {
+	ValidationErrors []ValidationError `json:"ValidationErrors"`
+}
+
+// ValidationError describes why an element was rejected.
+type ValidationError struct {
+	Message string `json:"Message"`
+}
+
+// newAPIError reads the status, headers and body of a non-200 response.
+// The body is parsed on a best-effort basis, as Xero does not return a JSON body for every status.
+func newAPIError(resp *http.Response, path string) *APIError {
+	apiErr := &APIError{
+		StatusCode:    resp.StatusCode,
+		Path:          path,
+		CorrelationID: resp.Header.Get("Xero-Correlation-Id"),
+		RetryAfter:    retryAfter(resp.Header, time.Now()),
+		ErrorNumber:   0,
+		Type:          "",
+		Message:       "",
+		Elements:      nil,
+	}
+
+	if body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil {
+		_ = json.Unmarshal(body, apiErr)
+	}
+
+	return apiErr
+}
+
+// Error satisfies the error interface.
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	b.WriteString(e.Unwrap().Error())
+	b.WriteString(": status " + strconv.Itoa(e.StatusCode) + " from " + e.Path)
+
+	if e.CorrelationID != "" {
+		b.WriteString(" (correlation ID " + e.CorrelationID + ")")
+	}
+
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+
+	if validation := e.ValidationErrors(); len(validation) > 0 {
+		b.WriteString(": " + strings.Join(validation, "; "))
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the sentinel error of the status code.
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrInvalidRequest
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrXeroDown
+	default:
+		return ErrUnexpectedStatus
+	}
+}
+
+// ValidationErrors returns the messages of all the validation errors, in order.
+func (e *APIError) ValidationErrors() []string {
+	messages := []string{}
+
+	for _, element := range e.Elements {
+		for _, validation := range element.ValidationErrors {
+			messages = append(messages, validation.Message)
+		}
+	}
+
+	return messages
+}
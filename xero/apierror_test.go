@@ -0,0 +1,113 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		body   []byte
+		header http.Header
+		status int
+		want   xero.APIError
+		text   string
+	}{
+		"validation error": {
+			body:   fixture(t, "testdata/validation-error.json"),
+			header: http.Header{"Xero-Correlation-Id": []string{"6f1e8b8c-54b3-4b0e-9f2c-0b8b1d2e3f4a"}},
+			status: http.StatusBadRequest,
+			want: xero.APIError{
+				StatusCode:    http.StatusBadRequest,
+				Path:          "/api.xro/2.0/Reports/BalanceSheet",
+				CorrelationID: "6f1e8b8c-54b3-4b0e-9f2c-0b8b1d2e3f4a",
+				ErrorNumber:   10,
+				Type:          "ValidationException",
+				Message:       "A validation exception occurred",
+				Elements: []xero.ErrorElement{
+					{
+						ValidationErrors: []xero.ValidationError{
+							{Message: "The date must be after 1 January 1900"},
+							{Message: "Tracking option is not valid"},
+						},
+					},
+				},
+			},
+			text: "invalid parameter: status 400 from /api.xro/2.0/Reports/BalanceSheet (correlation ID 6f1e8b8c-54b3-4b0e-9f2c-0b8b1d2e3f4a): " +
+				"A validation exception occurred: The date must be after 1 January 1900; Tracking option is not valid",
+		},
+		"rate limited": {
+			body:   []byte("Rate limit exceeded"),
+			header: http.Header{"Retry-After": []string{"30"}},
+			status: http.StatusTooManyRequests,
+			want: xero.APIError{
+				StatusCode: http.StatusTooManyRequests,
+				Path:       "/api.xro/2.0/Reports/BalanceSheet",
+				RetryAfter: 30 * time.Second,
+			},
+			text: "request hit the rate limit: status 429 from /api.xro/2.0/Reports/BalanceSheet",
+		},
+		"no body": {
+			body:   nil,
+			header: http.Header{},
+			status: http.StatusConflict,
+			want: xero.APIError{
+				StatusCode: http.StatusConflict,
+				Path:       "/api.xro/2.0/Reports/BalanceSheet",
+			},
+			text: "unexpected status in Xero response: status 409 from /api.xro/2.0/Reports/BalanceSheet",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &sequenceHTTPDoer{
+				responses: []mockResponse{{body: test.body, header: test.header, status: test.status}},
+			}
+
+			_, err := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+			var apiErr *xero.APIError
+
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, test.want, *apiErr)
+			assert.EqualError(t, err, test.text)
+		})
+	}
+}
+
+func TestAPIErrorLargeBody(t *testing.T) {
+	t.Parallel()
+
+	// Valid JSON, but the closing brace is past the size limit.
+	body := append([]byte(`{"Message": "A validation exception occurred"`), bytes.Repeat([]byte(" "), 1<<20)...)
+	body = append(body, '}')
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(mockHTTPDoer{
+			mockResponse: &http.Response{
+				Body:       io.NopCloser(bytes.NewReader(body)),
+				StatusCode: http.StatusBadRequest,
+			},
+		}).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+	var apiErr *xero.APIError
+
+	assert.ErrorIs(t, err, xero.ErrInvalidRequest)
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Empty(t, apiErr.Message)
+}
@@ -0,0 +1,305 @@
+package xero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTokenTimeout = 30 * time.Second                          // Default timeout of the token endpoint requests.
+	DefaultTokenURL     = "https://identity.xero.com/connect/token" // Default Xero identity endpoint.
+
+	tokenExpiryLeeway = time.Minute // Tokens are refreshed this long before they expire.
+)
+
+var (
+	ErrTokenFailure = errors.New("could not obtain Xero access token") // OAuth2 token endpoint returned an error.
+)
+
+// Token is an OAuth2 access token.
+type Token struct {
+	AccessToken  string
+	Expiry       time.Time // Zero value means the token never expires.
+	RefreshToken string
+	TokenType    string
+}
+
+// valid returns whether the token can still be used for at least tokenExpiryLeeway.
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || now.Add(tokenExpiryLeeway).Before(t.Expiry))
+}
+
+// TokenSource provides access tokens for the Authorization header.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// tokenInvalidator is implemented by token sources that can discard a token rejected by Xero.
+type tokenInvalidator interface {
+	Invalidate(token *Token) bool
+}
+
+// OAuthConfig contains the app credentials used to obtain access tokens.
+// See https://developer.xero.com/documentation/guides/oauth2/overview
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	HTTPClient   HTTPDoer      // Defaults to http.DefaultClient.
+	OnToken      func(Token)   // Optional, called after every refresh, e.g. to persist the rotated refresh token.
+	Scopes       []string      // Requested scopes, only used by the client credentials flow.
+	Timeout      time.Duration // Timeout of the token endpoint requests, defaults to DefaultTokenTimeout.
+	TokenURL     string        // Defaults to DefaultTokenURL.
+}
+
+// StaticToken returns a token source that always returns the given access token.
+func StaticToken(accessToken string) TokenSource {
+	return staticToken{
+		token: &Token{
+			AccessToken:  accessToken,
+			Expiry:       time.Time{},
+			RefreshToken: "",
+			TokenType:    "Bearer",
+		},
+	}
+}
+
+// RefreshTokenSource returns a token source that uses the refresh token flow, Xero rotates the refresh token on every use.
+// See https://developer.xero.com/documentation/guides/oauth2/auth-flow#refreshing-access-and-refresh-tokens
+func RefreshTokenSource(cfg OAuthConfig, refreshToken string) TokenSource {
+	return newRefreshingSource(cfg, func(current *Token) url.Values {
+		latest := refreshToken
+		if current != nil && current.RefreshToken != "" {
+			latest = current.RefreshToken
+		}
+
+		if latest == "" {
+			return nil
+		}
+
+		return url.Values{
+			"grant_type":    []string{"refresh_token"},
+			"refresh_token": []string{latest},
+		}
+	})
+}
+
+// ClientCredentialsSource returns a token source that uses the client credentials flow of custom connections.
+// See https://developer.xero.com/documentation/guides/oauth2/custom-connections
+func ClientCredentialsSource(cfg OAuthConfig) TokenSource {
+	return newRefreshingSource(cfg, func(*Token) url.Values {
+		form := url.Values{
+			"grant_type": []string{"client_credentials"},
+		}
+
+		if len(cfg.Scopes) > 0 {
+			form.Set("scope", strings.Join(cfg.Scopes, " "))
+		}
+
+		return form
+	})
+}
+
+// staticToken is a TokenSource that never changes.
+type staticToken struct {
+	token *Token
+}
+
+// Token satisfies the TokenSource interface.
+func (s staticToken) Token(context.Context) (*Token, error) {
+	return s.token, nil
+}
+
+// refreshingSource caches a token and fetches a new one from the token endpoint when it is about to expire.
+// Exchanges are serialised, so that concurrent callers share the outcome of a single refresh, but callers stop waiting
+// for it when their context is done. The exchange itself is not cancelled with the caller that started it, it is
+// bounded by the config's Timeout instead.
+type refreshingSource struct {
+	cfg       OAuthConfig
+	err       error                           // Error of the last exchange, if it failed.
+	exchanges chan struct{}                   // Holds a value while an exchange is in progress.
+	form      func(current *Token) url.Values // Returns nil if no grant can be made, e.g. without a refresh token.
+	mu        sync.Mutex                      // Guards err and token.
+	token     *Token
+}
+
+func newRefreshingSource(cfg OAuthConfig, form func(*Token) url.Values) *refreshingSource {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTokenTimeout
+	}
+
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = DefaultTokenURL
+	}
+
+	return &refreshingSource{
+		cfg:       cfg,
+		err:       nil,
+		exchanges: make(chan struct{}, 1),
+		form:      form,
+		mu:        sync.Mutex{},
+		token:     nil,
+	}
+}
+
+// Invalidate discards the given token if it is still the cached one, and returns whether a new token can be fetched.
+// It cannot if the last refresh failed or if there is no refresh token to use.
+func (s *refreshingSource) Invalidate(token *Token) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return false
+	}
+
+	if s.token != token {
+		return true // Already refreshed.
+	}
+
+	s.token = &Token{
+		AccessToken:  "",
+		Expiry:       time.Time{},
+		RefreshToken: token.RefreshToken,
+		TokenType:    "",
+	}
+
+	return s.form(s.token) != nil
+}
+
+// Token satisfies the TokenSource interface.
+func (s *refreshingSource) Token(ctx context.Context) (*Token, error) {
+	if token, ok := s.cached(); ok {
+		return token, nil
+	}
+
+	select {
+	case s.exchanges <- struct{}{}:
+	case <-ctx.Done():
+		return nil, errors.Join(ErrTokenFailure, ctx.Err())
+	}
+
+	defer func() { <-s.exchanges }()
+
+	// Another caller may have refreshed the token while this one was waiting.
+	if token, ok := s.cached(); ok {
+		return token, nil
+	}
+
+	s.mu.Lock()
+	current := s.token
+	s.mu.Unlock()
+
+	exchangeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.Timeout)
+	defer cancel()
+
+	token, err := s.exchange(exchangeCtx, current)
+
+	s.mu.Lock()
+
+	switch {
+	case err == nil:
+		s.err = nil
+		s.token = token
+	case !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded):
+		// Timeouts say nothing about the grant, a later refresh may still succeed.
+		s.err = err
+	}
+
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, errors.Join(ErrTokenFailure, err)
+	}
+
+	// Callbacks run outside of mu, so that a slow one does not block the callers of a valid token.
+	if s.cfg.OnToken != nil {
+		s.cfg.OnToken(*token)
+	}
+
+	return token, nil
+}
+
+// cached returns the cached token and whether it is still valid.
+func (s *refreshingSource) cached() (*Token, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token, s.token.valid(time.Now())
+}
+
+// tokenResponse is the payload returned by the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`  //nolint:tagliatelle // OAuth2 format
+	Error        string `json:"error"`         //nolint:tagliatelle // OAuth2 format
+	ExpiresIn    int    `json:"expires_in"`    //nolint:tagliatelle // OAuth2 format
+	RefreshToken string `json:"refresh_token"` //nolint:tagliatelle // OAuth2 format
+	TokenType    string `json:"token_type"`    //nolint:tagliatelle // OAuth2 format
+}
+
+// exchange posts the grant to the token endpoint, current is the token being replaced, if any.
+func (s *refreshingSource) exchange(ctx context.Context, current *Token) (*Token, error) {
+	var tr tokenResponse
+
+	form := s.form(current)
+	if form == nil {
+		return nil, errors.New("no refresh token") //nolint:err113 // Wrapped in ErrTokenFailure
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Join(ErrHTTPFailure, err)
+	}
+
+	req.SetBasicAuth(s.cfg.ClientID, s.cfg.ClientSecret)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Join(ErrRequestFailure, err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &tr)
+	}
+
+	switch {
+	case resp.StatusCode != http.StatusOK:
+		return nil, errors.New(strings.TrimSpace("token endpoint returned status " + strconv.Itoa(resp.StatusCode) + " " + tr.Error)) //nolint:err113 // This is just to expose the status code
+	case err != nil:
+		return nil, errors.Join(ErrInvalidResponse, err)
+	case tr.AccessToken == "":
+		return nil, errors.Join(ErrInvalidResponse, errors.New("missing access_token")) //nolint:err113 // Detail of ErrInvalidResponse
+	}
+
+	token := &Token{
+		AccessToken:  tr.AccessToken,
+		Expiry:       time.Time{},
+		RefreshToken: tr.RefreshToken,
+		TokenType:    tr.TokenType,
+	}
+
+	if token.RefreshToken == "" && current != nil {
+		token.RefreshToken = current.RefreshToken
+	}
+
+	if tr.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,388 @@
+package xero_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenServer is a local stand-in for Xero's identity endpoint, it issues tokens named after the number of exchanges.
+type tokenServer struct {
+	*httptest.Server
+
+	exchanges atomic.Int32
+	expiresIn int
+	forms     chan url.Values
+	status    int
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+
+	ts := &tokenServer{
+		expiresIn: expiresIn,
+		forms:     make(chan url.Values, 100),
+		status:    http.StatusOK,
+	}
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "client-id" || pass != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		ts.forms <- r.PostForm
+
+		if ts.status != http.StatusOK {
+			w.WriteHeader(ts.status)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+
+			return
+		}
+
+		n := ts.exchanges.Add(1)
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access-" + string(rune('0'+n)),
+			"expires_in":    ts.expiresIn,
+			"refresh_token": "refresh-" + string(rune('0'+n)),
+			"token_type":    "Bearer",
+		})
+	}))
+
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func (ts *tokenServer) config() xero.OAuthConfig {
+	return xero.OAuthConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Scopes:       []string{"accounting.reports.read", "accounting.settings.read"},
+		TokenURL:     ts.URL,
+	}
+}
+
+func TestStaticToken(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingHTTPDoer{
+		body:   fixture(t, "testdata/reports.json"),
+		status: http.StatusOK,
+	}
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithTokenSource(xero.StaticToken("my-token")).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer my-token", doer.requests[0].Header.Get("Authorization"))
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		expiresIn int
+		calls     int
+		auth      []string
+		refresh   []string
+	}{
+		"token is reused until it expires": {
+			expiresIn: 1800,
+			calls:     3,
+			auth:      []string{"Bearer access-1", "Bearer access-1", "Bearer access-1"},
+			refresh:   []string{"initial"},
+		},
+		"token is refreshed before it expires": {
+			expiresIn: 30,
+			calls:     3,
+			auth:      []string{"Bearer access-1", "Bearer access-2", "Bearer access-3"},
+			refresh:   []string{"initial", "refresh-1", "refresh-2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTokenServer(t, test.expiresIn)
+			doer := &recordingHTTPDoer{
+				body:   fixture(t, "testdata/reports.json"),
+				status: http.StatusOK,
+			}
+
+			client := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				WithTokenSource(xero.RefreshTokenSource(ts.config(), "initial"))
+
+			for range test.calls {
+				_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+				assert.NoError(t, err)
+			}
+
+			auth := make([]string, 0, len(doer.requests))
+			for _, req := range doer.requests {
+				auth = append(auth, req.Header.Get("Authorization"))
+			}
+
+			refresh := make([]string, 0, len(test.refresh))
+			for range test.refresh {
+				form := <-ts.forms
+
+				assert.Equal(t, "refresh_token", form.Get("grant_type"))
+
+				refresh = append(refresh, form.Get("refresh_token"))
+			}
+
+			assert.Equal(t, test.auth, auth)
+			assert.Equal(t, test.refresh, refresh)
+			assert.Empty(t, ts.forms)
+		})
+	}
+}
+
+func TestClientCredentialsSource(t *testing.T) {
+	t.Parallel()
+
+	ts := newTokenServer(t, 1800)
+
+	var refreshed []xero.Token
+
+	cfg := ts.config()
+	cfg.OnToken = func(token xero.Token) {
+		refreshed = append(refreshed, token)
+	}
+
+	token, err := xero.ClientCredentialsSource(cfg).Token(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Len(t, refreshed, 1)
+
+	form := <-ts.forms
+
+	assert.Equal(t, "client_credentials", form.Get("grant_type"))
+	assert.Equal(t, "accounting.reports.read accounting.settings.read", form.Get("scope"))
+}
+
+func TestTokenSourceError(t *testing.T) {
+	t.Parallel()
+
+	ts := newTokenServer(t, 1800)
+	ts.status = http.StatusBadRequest
+	doer := &recordingHTTPDoer{
+		body:   fixture(t, "testdata/reports.json"),
+		status: http.StatusOK,
+	}
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithTokenSource(xero.RefreshTokenSource(ts.config(), "revoked")).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+	assert.ErrorIs(t, err, xero.ErrTokenFailure)
+	assert.ErrorContains(t, err, "invalid_grant")
+	assert.Empty(t, doer.requests)
+}
+
+func TestTokenRefreshConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ts := newTokenServer(t, 1800)
+	source := xero.RefreshTokenSource(ts.config(), "initial")
+
+	var wg sync.WaitGroup
+
+	for range 20 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			token, err := source.Token(context.TODO())
+			assert.NoError(t, err)
+			assert.Equal(t, "access-1", token.AccessToken)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), ts.exchanges.Load())
+}
+
+func TestUnauthorizedRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		responses []int
+		calls     int
+		exchanges int32
+		err       bool
+	}{
+		"refresh and retry once": {
+			responses: []int{http.StatusUnauthorized, http.StatusOK},
+			calls:     2,
+			exchanges: 2,
+			err:       false,
+		},
+		"give up after the second 401": {
+			responses: []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK},
+			calls:     2,
+			exchanges: 2,
+			err:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTokenServer(t, 1800)
+			doer := &sequenceHTTPDoer{}
+
+			for _, status := range test.responses {
+				doer.responses = append(doer.responses, mockResponse{
+					body:   fixture(t, "testdata/reports.json"),
+					status: status,
+				})
+			}
+
+			_, err := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				WithTokenSource(xero.RefreshTokenSource(ts.config(), "initial")).
+				BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+			assert.Equal(t, test.calls, doer.Calls())
+			assert.Equal(t, test.exchanges, ts.exchanges.Load())
+
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// invalidator is implemented by the refreshing token sources.
+type invalidator interface {
+	Invalidate(token *xero.Token) bool
+}
+
+func TestInvalidate(t *testing.T) {
+	t.Parallel()
+
+	ts := newTokenServer(t, 1800)
+	source := xero.RefreshTokenSource(ts.config(), "initial")
+
+	token, err := source.Token(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, source.(invalidator).Invalidate(token))
+
+	// The refresh fails, there is nothing left to retry with.
+	ts.status = http.StatusBadRequest
+
+	_, err = source.Token(context.TODO())
+	assert.ErrorIs(t, err, xero.ErrTokenFailure)
+	assert.False(t, source.(invalidator).Invalidate(token))
+}
+
+func TestTokenRefreshWaiters(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	ts := newTokenServer(t, 1800)
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+
+		_, _ = w.Write([]byte(`{"access_token":"access-1","expires_in":1800}`))
+	})
+
+	source := xero.RefreshTokenSource(ts.config(), "initial")
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		token, err := source.Token(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, "access-1", token.AccessToken)
+	}()
+
+	// Waits for the refresh in progress until its own deadline.
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	time.Sleep(10 * time.Millisecond)
+
+	_, err := source.Token(ctx)
+	assert.ErrorIs(t, err, xero.ErrTokenFailure)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	<-done
+}
+
+func TestTokenRefreshCancelled(t *testing.T) {
+	t.Parallel()
+
+	var exchanges atomic.Int32
+
+	release, stuck := make(chan struct{}), make(chan struct{})
+	ts := newTokenServer(t, 1800)
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if exchanges.Add(1) > 1 {
+			<-stuck // Replies too late.
+		}
+
+		<-release
+
+		_, _ = w.Write([]byte(`{"access_token":"access-1","expires_in":1800,"refresh_token":"refresh-1"}`))
+	})
+
+	t.Cleanup(func() { close(stuck) }) // Before the server is closed.
+
+	cfg := ts.config()
+	cfg.Timeout = 200 * time.Millisecond
+	source := xero.RefreshTokenSource(cfg, "initial")
+
+	// The caller that started the refresh gives up, the exchange goes on for the others.
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	token, err := source.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+
+	// The next refresh times out, that does not turn the recovery from 401 off.
+	assert.True(t, source.(invalidator).Invalidate(token))
+
+	_, err = source.Token(context.TODO())
+	assert.ErrorIs(t, err, xero.ErrTokenFailure)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, source.(invalidator).Invalidate(token))
+}
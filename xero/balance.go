@@ -1,7 +1,10 @@
 // Package xero provides a REST API client for xero that sends and receives JSON data.
 package xero
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Attributes is a struct that represents Cell attributes.
 // https://developer.xero.com/documentation/api/accounting/reports#balance-sheet
@@ -21,6 +24,9 @@ type Cell struct {
 // https://developer.xero.com/documentation/api/accounting/reports#balance-sheet
 type ReportResponse struct {
 	Reports []Report `description:"Reports list" json:"Reports"`
+
+	FetchedAt time.Time `json:"-"` // When the reports were received from Xero.
+	Stale     bool      `json:"-"` // Served from the cache past its TTL, see WithCache.
 }
 
 // Report is a struct that represents a Xero Report.
@@ -0,0 +1,132 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+)
+
+// fixtureHTTPDoer returns the same body with status 200 for every request.
+type fixtureHTTPDoer struct {
+	body []byte
+}
+
+func (m fixtureHTTPDoer) Do(_ *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Body:          io.NopCloser(bytes.NewReader(m.body)),
+		ContentLength: int64(len(m.body)),
+		Header:        http.Header{},
+		StatusCode:    http.StatusOK,
+	}, nil
+}
+
+func BenchmarkBalanceSheet(b *testing.B) {
+	benchmarks := map[string][]byte{
+		"reports":       fixture(b, "testdata/reports.json"),
+		"balance sheet": fixture(b, "testdata/balance-sheet.json"),
+		"large report":  largeReport(b, 100),
+	}
+
+	for name, body := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			client := xero.HTTPClient(nil).
+				WithHTTPClient(fixtureHTTPDoer{body: body}).
+				WithRateLimits(xero.RateLimits{})
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+
+			for range b.N {
+				if _, err := client.BalanceSheet(context.Background(), xero.BalanceSheetParams{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// The client used to read the whole body, then unmarshal it once for the Status and once for the reports.
+		// Only the decoding is measured here, so the client above does more work than this.
+		b.Run(name+" - read all", func(b *testing.B) {
+			doer := fixtureHTTPDoer{body: body}
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+
+			for range b.N {
+				if _, err := readAll(doer); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// readAll decodes the balance sheet as the client did before decoding in a single pass.
+func readAll(doer xero.HTTPDoer) (*xero.ReportResponse, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://xero.test/api.xro/2.0/Reports/BalanceSheet", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res xero.Response
+		rr  xero.ReportResponse
+	)
+
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.OK() {
+		return nil, xero.ErrBrokenResponse
+	}
+
+	if err = json.Unmarshal(data, &rr); err != nil {
+		return nil, err
+	}
+
+	return &rr, nil
+}
+
+// largeReport repeats the sections of the balance sheet fixture, as in reports with many periods and tracking categories.
+func largeReport(b *testing.B, copies int) []byte {
+	b.Helper()
+
+	var doc struct {
+		Status  string
+		Reports []xero.Report
+	}
+
+	if err := json.Unmarshal(fixture(b, "testdata/balance-sheet.json"), &doc); err != nil {
+		b.Fatal(err)
+	}
+
+	rows := doc.Reports[0].Rows
+
+	for range copies {
+		doc.Reports[0].Rows = append(doc.Reports[0].Rows, rows[1:]...)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return data
+}
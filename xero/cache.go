@@ -0,0 +1,245 @@
+package xero
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+const refreshTimeout = 30 * time.Second // Maximum duration of a background refresh.
+
+// CacheConfig configures the report cache, see WithCache.
+// Reports are cached by tenant, report type and parameters.
+type CacheConfig struct {
+	MaxEntries           int           // Maximum number of reports kept, the least recently used are evicted first.
+	TTL                  time.Duration // How long a report is served without contacting Xero.
+	StaleWhileRevalidate time.Duration // How long after the TTL an expired report is served while refreshed in the background.
+}
+
+// DefaultCacheConfig returns a cache configuration suitable for reports that rarely change minute to minute.
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxEntries:           256,             //nolint:mnd // Sensible default
+		TTL:                  5 * time.Minute, //nolint:mnd // Sensible default
+		StaleWhileRevalidate: 5 * time.Minute, //nolint:mnd // Sensible default
+	}
+}
+
+// reportCache is a size-bounded LRU cache of reports.
+// Entries are not removed when they expire, so that they can be served if Xero is down.
+type reportCache struct {
+	config  CacheConfig
+	entries map[string]*list.Element // Values are *cacheEntry.
+	lru     *list.List               // Most recently used first.
+	mu      sync.Mutex
+}
+
+// cacheEntry is a cached report.
+type cacheEntry struct {
+	fetchedAt  time.Time
+	key        string
+	refreshing bool // A background refresh is in progress.
+	rr         *ReportResponse
+}
+
+func newReportCache(config CacheConfig) *reportCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultCacheConfig().MaxEntries
+	}
+
+	return &reportCache{
+		config:  config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		mu:      sync.Mutex{},
+	}
+}
+
+// get returns the cached report and when it was fetched.
+func (rc *reportCache) get(key string) (*ReportResponse, time.Time, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+
+	rc.lru.MoveToFront(elem)
+
+	entry, _ := elem.Value.(*cacheEntry)
+
+	return entry.rr, entry.fetchedAt, true
+}
+
+// put stores the report, evicting the least recently used ones if the cache is full.
+func (rc *reportCache) put(key string, rr *ReportResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if elem, ok := rc.entries[key]; ok {
+		entry, _ := elem.Value.(*cacheEntry)
+		entry.fetchedAt = rr.FetchedAt
+		entry.rr = rr
+
+		rc.lru.MoveToFront(elem)
+
+		return
+	}
+
+	rc.entries[key] = rc.lru.PushFront(&cacheEntry{fetchedAt: rr.FetchedAt, key: key, refreshing: false, rr: rr})
+
+	for rc.lru.Len() > rc.config.MaxEntries {
+		oldest := rc.lru.Back()
+		entry, _ := oldest.Value.(*cacheEntry)
+
+		rc.lru.Remove(oldest)
+		delete(rc.entries, entry.key)
+	}
+}
+
+// startRefresh marks the entry as being refreshed, it returns false if a refresh is already in progress.
+func (rc *reportCache) startRefresh(key string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		return false
+	}
+
+	entry, _ := elem.Value.(*cacheEntry)
+	if entry.refreshing {
+		return false
+	}
+
+	entry.refreshing = true
+
+	return true
+}
+
+// endRefresh marks the end of the refresh of the entry, if it was not evicted in the meantime.
+func (rc *reportCache) endRefresh(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if elem, ok := rc.entries[key]; ok {
+		entry, _ := elem.Value.(*cacheEntry)
+		entry.refreshing = false
+	}
+}
+
+// cachedReport serves the report from the cache when possible:
+// - fresh reports are returned as they are;
+// - reports expired for less than StaleWhileRevalidate are returned as stale, and refreshed in the background;
+// - other reports are fetched from Xero, and the cached copy is returned as stale if Xero is down or unreachable.
+func (c *client) cachedReport(ctx context.Context, cl call) (*ReportResponse, error) {
+	key := cl.key()
+	cached, fetchedAt, ok := c.cache.get(key)
+	age := time.Since(fetchedAt)
+
+	switch {
+	case ok && age < c.cache.config.TTL:
+		return served(cached, false), nil
+	case ok && age < c.cache.config.TTL+c.cache.config.StaleWhileRevalidate:
+		c.revalidate(ctx, key, cl)
+
+		return served(cached, true), nil
+	}
+
+	rr, err := c.fetchReport(ctx, cl)
+
+	switch {
+	case err == nil:
+		c.cache.put(key, rr)
+
+		return served(rr, false), nil
+	case ok && (errors.Is(err, ErrXeroDown) || errors.Is(err, ErrRequestFailure)):
+		c.logger.Warn("Xero is down or unreachable, serving cached report", "endpoint", cl.path, "tenant", cl.tenantID, "age", age, "err", err)
+
+		return served(cached, true), nil
+	default:
+		return nil, err
+	}
+}
+
+// revalidate refreshes the cached report in the background, unless a refresh is already in progress.
+func (c *client) revalidate(ctx context.Context, key string, cl call) {
+	if !c.cache.startRefresh(key) {
+		return
+	}
+
+	go func() {
+		defer c.cache.endRefresh(key)
+
+		// The refresh outlives the request that triggered it.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		rr, err := c.fetchReport(ctx, cl)
+		if err != nil {
+			c.logger.Warn("Could not refresh cached report", "endpoint", cl.path, "tenant", cl.tenantID, "err", err)
+
+			return
+		}
+
+		c.cache.put(key, rr)
+	}()
+}
+
+// served returns a deep copy of the cached report, so that callers cannot change the cached one.
+func served(rr *ReportResponse, stale bool) *ReportResponse {
+	copied := *rr
+	copied.Reports = make([]Report, len(rr.Reports))
+	copied.Stale = stale
+
+	for i, report := range rr.Reports {
+		report.Fields = cloneFields(report.Fields)
+		report.ReportTitles = slices.Clone(report.ReportTitles)
+		report.Rows = cloneRows(report.Rows)
+		copied.Reports[i] = report
+	}
+
+	return &copied
+}
+
+// cloneFields returns a deep copy of the report fields.
+func cloneFields(fields []json.RawMessage) []json.RawMessage {
+	if fields == nil {
+		return nil
+	}
+
+	cloned := make([]json.RawMessage, len(fields))
+
+	for i, field := range fields {
+		cloned[i] = slices.Clone(field)
+	}
+
+	return cloned
+}
+
+// cloneRows returns a deep copy of the rows, with their cells and nested rows.
+func cloneRows(rows []Row) []Row {
+	if rows == nil {
+		return nil
+	}
+
+	cloned := make([]Row, len(rows))
+
+	for i, row := range rows {
+		row.Cells = slices.Clone(row.Cells)
+
+		for j := range row.Cells {
+			row.Cells[j].Attributes = slices.Clone(row.Cells[j].Attributes)
+		}
+
+		row.Rows = cloneRows(row.Rows)
+		cloned[i] = row
+	}
+
+	return cloned
+}
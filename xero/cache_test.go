@@ -0,0 +1,251 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// switchingHTTPDoer returns the fixture with the current status, or fails with the current error, and counts the
+// requests. It is safe for concurrent use.
+type switchingHTTPDoer struct {
+	body   []byte
+	calls  int
+	err    error
+	mu     sync.Mutex
+	status int
+}
+
+func (m *switchingHTTPDoer) Do(_ *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls++
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader(m.body)),
+		Header:     http.Header{},
+		StatusCode: m.status,
+	}, nil
+}
+
+func (m *switchingHTTPDoer) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls
+}
+
+func (m *switchingHTTPDoer) SetError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = err
+}
+
+func (m *switchingHTTPDoer) SetStatus(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = status
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	body := fixture(t, "testdata/reports.json")
+
+	tests := map[string]struct {
+		config xero.CacheConfig
+		// Second call, after the status and the transport error have been switched.
+		status  int
+		failure error
+		calls   int
+		err     error
+		stale   bool
+	}{
+		"fresh hit": {
+			config: xero.CacheConfig{MaxEntries: 10, TTL: time.Hour, StaleWhileRevalidate: 0},
+			status: http.StatusServiceUnavailable,
+			calls:  1,
+			err:    nil,
+			stale:  false,
+		},
+		"expired - refetched": {
+			config: xero.CacheConfig{MaxEntries: 10, TTL: time.Nanosecond, StaleWhileRevalidate: time.Nanosecond},
+			status: http.StatusOK,
+			calls:  2,
+			err:    nil,
+			stale:  false,
+		},
+		"expired - Xero down": {
+			config: xero.CacheConfig{MaxEntries: 10, TTL: time.Nanosecond, StaleWhileRevalidate: time.Nanosecond},
+			status: http.StatusServiceUnavailable,
+			calls:  2,
+			err:    nil,
+			stale:  true,
+		},
+		"expired - Xero unreachable": {
+			config:  xero.CacheConfig{MaxEntries: 10, TTL: time.Nanosecond, StaleWhileRevalidate: time.Nanosecond},
+			status:  http.StatusOK,
+			failure: errors.New("connection refused"),
+			calls:   2,
+			err:     nil,
+			stale:   true,
+		},
+		"expired - other errors are not masked": {
+			config: xero.CacheConfig{MaxEntries: 10, TTL: time.Nanosecond, StaleWhileRevalidate: time.Nanosecond},
+			status: http.StatusForbidden,
+			calls:  2,
+			err:    xero.ErrForbidden,
+			stale:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &switchingHTTPDoer{body: body, status: http.StatusOK}
+			client := xero.HTTPClient(nil).
+				WithCache(test.config).
+				WithHTTPClient(doer)
+
+			first, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+			assert.NoError(t, err)
+			assert.False(t, first.Stale)
+			assert.False(t, first.FetchedAt.IsZero())
+
+			time.Sleep(time.Millisecond)
+			doer.SetStatus(test.status)
+			doer.SetError(test.failure)
+
+			second, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+			assert.Equal(t, test.calls, doer.Calls())
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Nil(t, second)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.stale, second.Stale)
+			assert.Len(t, second.Reports, 1)
+		})
+	}
+}
+
+func TestCacheCopies(t *testing.T) {
+	t.Parallel()
+
+	client := xero.HTTPClient(nil).
+		WithCache(xero.CacheConfig{MaxEntries: 10, TTL: time.Hour, StaleWhileRevalidate: 0}).
+		WithHTTPClient(&switchingHTTPDoer{body: fixture(t, "testdata/reports.json"), status: http.StatusOK})
+
+	first, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	want := first.Reports[0].Rows[0].Cells[1].Value
+
+	first.Reports[0].ReportTitles[0] = "changed"
+	first.Reports[0].Rows[0].Cells[1].Value = "changed"
+	first.Reports[0].Rows = append(first.Reports[0].Rows[:0], xero.Row{})
+
+	second, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "changed", second.Reports[0].ReportTitles[0])
+	assert.Equal(t, want, second.Reports[0].Rows[0].Cells[1].Value)
+}
+
+func TestCacheKeys(t *testing.T) {
+	t.Parallel()
+
+	doer := &switchingHTTPDoer{body: fixture(t, "testdata/reports.json"), status: http.StatusOK}
+	client := xero.HTTPClient(nil).
+		WithCache(xero.DefaultCacheConfig()).
+		WithHTTPClient(doer)
+
+	calls := []struct {
+		ctx    context.Context //nolint:containedctx // Test table
+		params xero.BalanceSheetParams
+	}{
+		{ctx: xero.ContextWithTenant(context.TODO(), "0f8d3b5e-6a1c-4f3b-9b7e-2d4c8a1e5f60"), params: xero.BalanceSheetParams{}},
+		{ctx: xero.ContextWithTenant(context.TODO(), "a3c9e7d1-2b4f-4e8a-8c6d-9f1b3e5a7c20"), params: xero.BalanceSheetParams{}},
+		{ctx: xero.ContextWithTenant(context.TODO(), "0f8d3b5e-6a1c-4f3b-9b7e-2d4c8a1e5f60"), params: xero.BalanceSheetParams{Date: "2024-08-31"}},
+		{ctx: xero.ContextWithTenant(context.TODO(), "0f8d3b5e-6a1c-4f3b-9b7e-2d4c8a1e5f60"), params: xero.BalanceSheetParams{}},
+		{ctx: xero.ContextWithTenant(context.TODO(), "a3c9e7d1-2b4f-4e8a-8c6d-9f1b3e5a7c20"), params: xero.BalanceSheetParams{}},
+	}
+
+	for _, call := range calls {
+		_, err := client.BalanceSheet(call.ctx, call.params)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, doer.Calls())
+
+	// Other reports do not share the entries of the balance sheet.
+	_, err := client.TrialBalance(xero.ContextWithTenant(context.TODO(), "0f8d3b5e-6a1c-4f3b-9b7e-2d4c8a1e5f60"), xero.TrialBalanceParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, doer.Calls())
+}
+
+func TestCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	doer := &switchingHTTPDoer{body: fixture(t, "testdata/reports.json"), status: http.StatusOK}
+	client := xero.HTTPClient(nil).
+		WithCache(xero.CacheConfig{MaxEntries: 2, TTL: time.Hour, StaleWhileRevalidate: 0}).
+		WithHTTPClient(doer)
+
+	for _, date := range []string{"2024-01-31", "2024-02-29", "2024-01-31", "2024-03-31", "2024-01-31", "2024-02-29"} {
+		_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{Date: date})
+		assert.NoError(t, err)
+	}
+
+	// January stays in the cache as the most recently used, February is evicted by March.
+	assert.Equal(t, 4, doer.Calls())
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	doer := &switchingHTTPDoer{body: fixture(t, "testdata/reports.json"), status: http.StatusOK}
+	client := xero.HTTPClient(nil).
+		WithCache(xero.CacheConfig{MaxEntries: 10, TTL: time.Nanosecond, StaleWhileRevalidate: time.Hour}).
+		WithHTTPClient(doer)
+
+	first, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+
+	second, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.True(t, second.Stale)
+	assert.Equal(t, first.FetchedAt, second.FetchedAt)
+
+	// The stale report is refreshed in the background.
+	assert.Eventually(t, func() bool {
+		third, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+		return err == nil && third.FetchedAt.After(first.FetchedAt)
+	}, time.Second, time.Millisecond)
+
+	// The copy served earlier is unaffected.
+	assert.True(t, second.Stale)
+	assert.False(t, first.Stale)
+}
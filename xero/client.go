@@ -2,17 +2,24 @@ package xero
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
-	"strconv"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/luca-arch/code-drills/metrics"
 )
 
-const DefaultBaseURL = "https://api.xero.com" // Default Xero API domain.
+const (
+	DefaultBaseURL     = "https://api.xero.com" // Default Xero API domain.
+	DefaultMaxBodySize = 32 << 20               // Default maximum size of a response body, 32MiB.
+)
 
 var (
+	ErrBodyTooLarge    = errors.New("xero response body is too large")                // Xero response body exceeds the client's maximum size.
 	ErrBrokenResponse  = errors.New("xero response with error")                       // Xero response status (in the body) not OK .
 	ErrHTTPFailure     = errors.New("internal error")                                 // HTTP transport error.
 	ErrInvalidJSON     = errors.New("error while unmarshalling Xero reports")         // Xero API returned malformed JSON.
@@ -27,9 +34,19 @@ type HTTPDoer interface {
 
 // client defines a concrete type to invoke the Xero API.
 type client struct {
-	base   string
-	client HTTPDoer
-	logger *slog.Logger
+	base        string
+	cache       *reportCache
+	client      HTTPDoer
+	doer        HTTPDoer // The client, wrapped by the middlewares.
+	flights     *coalescer
+	limiter     *rateLimiter
+	logger      *slog.Logger
+	maxBodySize int64
+	metrics     *clientMetrics
+	middlewares []Middleware
+	retry       RetryPolicy
+	tenantID    string
+	tokens      TokenSource
 }
 
 // HTTPClient returns a new Xero HTTP client with default configuration.
@@ -41,68 +58,154 @@ func HTTPClient(logger *slog.Logger) *client { //nolint:revive // Ok to return t
 	logger.Debug("initialising new Xero HTTP client")
 
 	return &client{
-		base:   DefaultBaseURL,
-		client: http.DefaultClient,
-		logger: logger,
+		base:        DefaultBaseURL,
+		cache:       nil,
+		client:      http.DefaultClient,
+		doer:        http.DefaultClient,
+		flights:     newCoalescer(),
+		limiter:     newRateLimiter(DefaultRateLimits()),
+		logger:      logger,
+		maxBodySize: DefaultMaxBodySize,
+		metrics:     nil,
+		middlewares: nil,
+		retry:       RetryPolicy{MaxAttempts: 1, BaseDelay: 0, MaxDelay: 0},
+		tenantID:    "",
+		tokens:      nil,
+	}
+}
+
+// AgedPayablesByContact invokes the Reports AgedPayablesByContact endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#aged-payables-by-contact
+func (c *client) AgedPayablesByContact(ctx context.Context, params AgedReportParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.report(ctx, "AgedPayablesByContact", params.Query(), opts)
+}
+
+// AgedReceivablesByContact invokes the Reports AgedReceivablesByContact endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#aged-receivables-by-contact
+func (c *client) AgedReceivablesByContact(ctx context.Context, params AgedReportParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
+
+	return c.report(ctx, "AgedReceivablesByContact", params.Query(), opts)
 }
 
 // BalanceSheet invokes the Reports BalanceSheet endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
 // See https://developer.xero.com/documentation/api/accounting/reports#balance-sheet
-func (c *client) BalanceSheet(ctx context.Context) (*ReportResponse, error) {
-	var (
-		r  Response
-		rr ReportResponse
-	)
+func (c *client) BalanceSheet(ctx context.Context, params BalanceSheetParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 
-	c.logger.Debug("Outgoing HTTP request", "endpoint", " /api.xro/2.0/Reports/BalanceSheet")
+	return c.report(ctx, "BalanceSheet", params.Query(), opts)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/api.xro/2.0/Reports/BalanceSheet", nil)
-	if err != nil {
-		return nil, errors.Join(ErrHTTPFailure, err)
+// ProfitAndLoss invokes the Reports ProfitAndLoss endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#profit-and-loss
+func (c *client) ProfitAndLoss(ctx context.Context, params ProfitAndLossParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json")
+	return c.report(ctx, "ProfitAndLoss", params.Query(), opts)
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, errors.Join(ErrRequestFailure, err)
+// TrialBalance invokes the Reports TrialBalance endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#trial-balance
+func (c *client) TrialBalance(ctx context.Context, params TrialBalanceParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
-	c.logger.Debug("HTTP request finished", "status", resp.StatusCode)
-
-	defer resp.Body.Close()
-
-	switch {
-	case resp.StatusCode == http.StatusOK:
-		break
-	case resp.StatusCode == http.StatusBadRequest:
-		return nil, ErrInvalidRequest
-	case resp.StatusCode == http.StatusTooManyRequests:
-		return nil, ErrTooManyRequests
-	case resp.StatusCode >= http.StatusInternalServerError:
-		return nil, ErrXeroDown
-	default:
-		return nil, errors.New("invalid status in Xero response: " + strconv.Itoa(resp.StatusCode)) //nolint:err113 // This is just to expose the status code
+	return c.report(ctx, "TrialBalance", params.Query(), opts)
+}
+
+// BankSummary invokes the Reports BankSummary endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#bank-summary
+func (c *client) BankSummary(ctx context.Context, params BankSummaryParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err == nil {
-		err = json.Unmarshal(body, &r)
+	return c.report(ctx, "BankSummary", params.Query(), opts)
+}
+
+// ExecutiveSummary invokes the Reports ExecutiveSummary endpoint and returns a list of reports.
+// Parameters are validated before sending the request, a *ParamError is returned if any is invalid.
+// See https://developer.xero.com/documentation/api/accounting/reports#executive-summary
+func (c *client) ExecutiveSummary(ctx context.Context, params ExecutiveSummaryParams, opts ...CallOption) (*ReportResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
-	switch {
-	case err != nil:
-		return nil, errors.Join(ErrInvalidResponse, err)
-	case !r.OK():
-		return nil, ErrBrokenResponse
+	return c.report(ctx, "ExecutiveSummary", params.Query(), opts)
+}
+
+// report invokes the given Reports endpoint and decodes the list of reports.
+func (c *client) report(ctx context.Context, name string, query url.Values, opts []CallOption) (*ReportResponse, error) {
+	tenantID, err := c.tenant(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = json.Unmarshal(body, &rr); err != nil {
-		return nil, errors.Join(ErrInvalidJSON, err)
+	cl := call{
+		path:     "/api.xro/2.0/Reports/" + name,
+		query:    query,
+		tenantID: tenantID,
+	}
+
+	if c.cache != nil {
+		return c.cachedReport(ctx, cl)
+	}
+
+	return c.fetchReport(ctx, cl)
+}
+
+// fetchReport invokes a Reports endpoint, bypassing the cache.
+// Identical concurrent calls are merged into a single request.
+func (c *client) fetchReport(ctx context.Context, cl call) (*ReportResponse, error) {
+	return c.flights.do(ctx, cl.key(), func(ctx context.Context) (*ReportResponse, error) {
+		envelope, err := fetch[reportEnvelope](ctx, c, cl)
+		if err != nil {
+			return nil, err
+		}
+
+		envelope.FetchedAt = time.Now()
+
+		return &envelope.ReportResponse, nil
+	})
+}
+
+// reportEnvelope is the body of the Reports endpoints, where the list of reports comes along with the Status.
+type reportEnvelope struct {
+	Response
+	ReportResponse
+}
+
+// chain wraps the HTTP doer with the middlewares, the client's latency metrics are measured closest to Xero.
+func (c *client) chain() {
+	middlewares := c.middlewares
+
+	if c.metrics != nil {
+		middlewares = append(slices.Clone(middlewares), MeasureLatency(c.metrics.latency))
 	}
 
-	return &rr, nil
+	c.doer = Chain(c.client, middlewares...)
+}
+
+// RateBudget returns the number of calls that can be made for the given tenant before hitting Xero's rate limits.
+func (c *client) RateBudget(tenantID string) RateBudget {
+	return c.limiter.budget(tenantID)
 }
 
 // WithBaseURL sets the client's base URL.
@@ -112,9 +215,71 @@ func (c *client) WithBaseURL(base string) *client {
 	return c
 }
 
+// WithCache enables the report cache, reports are always fetched from Xero by default.
+func (c *client) WithCache(config CacheConfig) *client {
+	c.cache = newReportCache(config)
+
+	return c
+}
+
 // WithHTTPClient sets the client's HTTP doer.
 func (c *client) WithHTTPClient(client HTTPDoer) *client {
 	c.client = client
+	c.chain()
+
+	return c
+}
+
+// WithMaxBodySize sets the maximum size of the response bodies, larger ones fail with ErrBodyTooLarge.
+// DefaultMaxBodySize applies by default, zero or negative values disable the limit.
+func (c *client) WithMaxBodySize(size int64) *client {
+	c.maxBodySize = size
+
+	return c
+}
+
+// WithMetrics registers the client's metrics in reg, see newClientMetrics. No metrics are recorded by default.
+func (c *client) WithMetrics(reg *metrics.Registry) *client {
+	c.metrics = newClientMetrics(reg, func() *rateLimiter { return c.limiter })
+	c.chain()
+
+	return c
+}
+
+// WithMiddlewares adds middlewares around the client's HTTP doer, see Chain for the order they run in.
+// They apply to every attempt of a call, after the rate limiter and the Authorization header.
+func (c *client) WithMiddlewares(middlewares ...Middleware) *client {
+	c.middlewares = append(c.middlewares, middlewares...)
+	c.chain()
+
+	return c
+}
+
+// WithRateLimits replaces the client's rate limiter, DefaultRateLimits are applied by default.
+func (c *client) WithRateLimits(limits RateLimits) *client {
+	c.limiter = newRateLimiter(limits)
+
+	return c
+}
+
+// WithRetryPolicy sets the client's retry policy, retries are disabled by default.
+func (c *client) WithRetryPolicy(policy RetryPolicy) *client {
+	c.retry = policy
+
+	return c
+}
+
+// WithTenant sets the tenant used when neither the call options nor the context carry one.
+func (c *client) WithTenant(tenantID string) *client {
+	c.tenantID = tenantID
+
+	return c
+}
+
+// WithTokenSource sets the source of the access tokens sent in the Authorization header.
+// No Authorization header is sent by default.
+func (c *client) WithTokenSource(tokens TokenSource) *client {
+	c.tokens = tokens
 
 	return c
 }
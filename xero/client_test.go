@@ -24,6 +24,23 @@ func (m mockHTTPDoer) Do(_ *http.Request) (*http.Response, error) {
 	return m.mockResponse, m.mockError
 }
 
+// recordingHTTPDoer returns a fresh response with the same body and status for every request and keeps track of them.
+type recordingHTTPDoer struct {
+	body     []byte
+	requests []*http.Request
+	status   int
+}
+
+func (m *recordingHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader(m.body)),
+		Header:     http.Header{},
+		StatusCode: m.status,
+	}, nil
+}
+
 func mockReadCloser(t *testing.T) io.ReadCloser {
 	t.Helper()
 
@@ -45,10 +62,25 @@ func TestBalanceSheetError(t *testing.T) {
 			mockStatus:    http.StatusBadRequest,
 			expectedError: xero.ErrInvalidRequest,
 		},
+		"HTTP 401": {
+			mockError:     nil,
+			mockStatus:    http.StatusUnauthorized,
+			expectedError: xero.ErrUnauthorized,
+		},
+		"HTTP 403": {
+			mockError:     nil,
+			mockStatus:    http.StatusForbidden,
+			expectedError: xero.ErrForbidden,
+		},
 		"HTTP 404": {
 			mockError:     nil,
 			mockStatus:    http.StatusNotFound,
-			expectedError: errors.New("invalid status in Xero response: 404"),
+			expectedError: xero.ErrNotFound,
+		},
+		"HTTP 409": {
+			mockError:     nil,
+			mockStatus:    http.StatusConflict,
+			expectedError: xero.ErrUnexpectedStatus,
 		},
 		"HTTP 429": {
 			mockError:     nil,
@@ -83,7 +115,7 @@ func TestBalanceSheetError(t *testing.T) {
 		"Network error": {
 			mockError:     errors.New("mock TCP err"),
 			mockStatus:    0,
-			expectedError: errors.New("error while retrieving Balance Sheet from Xero\nmock TCP err"),
+			expectedError: xero.ErrRequestFailure,
 		},
 	}
 
@@ -100,10 +132,22 @@ func TestBalanceSheetError(t *testing.T) {
 					},
 				})
 
-			resp, err := client.BalanceSheet(context.TODO())
+			resp, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
 
 			assert.Nil(t, resp)
-			assert.EqualError(t, err, test.expectedError.Error())
+			assert.ErrorIs(t, err, test.expectedError)
+
+			var apiErr *xero.APIError
+
+			if test.mockError != nil {
+				assert.False(t, errors.As(err, &apiErr))
+
+				return
+			}
+
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, test.mockStatus, apiErr.StatusCode)
+			assert.Equal(t, "/api.xro/2.0/Reports/BalanceSheet", apiErr.Path)
 		})
 	}
 }
@@ -140,7 +184,7 @@ func TestBalanceSheetResponse(t *testing.T) {
 						"Title 02",
 					},
 					ReportDate:     "25 August 2024",
-					UpdatedDateUTC: xeroDTField(t, 1724595191),
+					UpdatedDateUTC: xeroDTField(t, 1724595191626),
 					Rows: []xero.Row{
 						{
 							RowType: "Header",
@@ -211,6 +255,33 @@ func TestBalanceSheetResponse(t *testing.T) {
 				err: xero.ErrInvalidJSON,
 			},
 		},
+		"error - response.Body.Status not OK and Reports invalid": {
+			fields{
+				body:   []byte(`{"Status": "ko", "Reports": "0123456789"}`),
+				status: http.StatusOK,
+			},
+			wants{
+				err: xero.ErrBrokenResponse,
+			},
+		},
+		"error - rows invalid before a ko Status": {
+			fields{
+				body:   []byte(`{"Id": "1", "Reports": [{"ReportID": "BalanceSheet", "Rows": [{"RowType": 1}]}], "Status": "ko"}`),
+				status: http.StatusOK,
+			},
+			wants{
+				err: xero.ErrBrokenResponse,
+			},
+		},
+		"error - truncated rows": {
+			fields{
+				body:   []byte(`{"Status": "OK", "Reports": [{"ReportID": "BalanceSheet", "Rows": [{"RowType": "Header"}, {"Row`),
+				status: http.StatusOK,
+			},
+			wants{
+				err: xero.ErrInvalidResponse,
+			},
+		},
 		"error - invalid content type": {
 			fields{
 				body:   []byte("hello"),
@@ -235,7 +306,7 @@ func TestBalanceSheetResponse(t *testing.T) {
 					},
 				})
 
-			resp, err := client.BalanceSheet(context.TODO())
+			resp, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
 
 			if test.wants.err == nil {
 				assert.NoError(t, err)
@@ -249,7 +320,332 @@ func TestBalanceSheetResponse(t *testing.T) {
 	}
 }
 
-func fixture(t *testing.T, path string) []byte {
+func TestBalanceSheetRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		params xero.BalanceSheetParams
+		url    string
+		err    error
+	}{
+		"no parameters": {
+			params: xero.BalanceSheetParams{},
+			url:    "http://xero.test/api.xro/2.0/Reports/BalanceSheet",
+			err:    nil,
+		},
+		"with parameters": {
+			params: xero.BalanceSheetParams{
+				Date:      "2024-08-25",
+				Periods:   2,
+				Timeframe: xero.TimeframeYear,
+			},
+			url: "http://xero.test/api.xro/2.0/Reports/BalanceSheet?date=2024-08-25&periods=2&timeframe=YEAR",
+			err: nil,
+		},
+		"invalid parameters are not sent": {
+			params: xero.BalanceSheetParams{
+				Periods: 12,
+			},
+			url: "",
+			err: xero.ErrInvalidParam,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{
+				body:   fixture(t, "testdata/reports.json"),
+				status: http.StatusOK,
+			}
+
+			_, err := xero.HTTPClient(nil).
+				WithBaseURL("http://xero.test").
+				WithHTTPClient(doer).
+				BalanceSheet(context.TODO(), test.params)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, doer.requests)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, doer.requests, 1)
+			assert.Equal(t, test.url, doer.requests[0].URL.String())
+		})
+	}
+}
+
+func TestProfitAndLoss(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		params xero.ProfitAndLossParams
+		url    string
+		err    error
+	}{
+		"no parameters": {
+			params: xero.ProfitAndLossParams{},
+			url:    "http://xero.test/api.xro/2.0/Reports/ProfitAndLoss",
+			err:    nil,
+		},
+		"with parameters": {
+			params: xero.ProfitAndLossParams{
+				FromDate:           "2024-07-01",
+				ToDate:             "2024-07-31",
+				TrackingCategoryID: "8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+				PaymentsOnly:       true,
+			},
+			url: "http://xero.test/api.xro/2.0/Reports/ProfitAndLoss?fromDate=2024-07-01&paymentsOnly=true&toDate=2024-07-31" +
+				"&trackingCategoryID=8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+			err: nil,
+		},
+		"invalid parameters are not sent": {
+			params: xero.ProfitAndLossParams{
+				FromDate: "2024-07-31",
+				ToDate:   "2024-07-01",
+			},
+			url: "",
+			err: xero.ErrInvalidParam,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{
+				body:   fixture(t, "testdata/profit-and-loss.json"),
+				status: http.StatusOK,
+			}
+
+			resp, err := xero.HTTPClient(nil).
+				WithBaseURL("http://xero.test").
+				WithHTTPClient(doer).
+				ProfitAndLoss(context.TODO(), test.params)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, doer.requests)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, doer.requests, 1)
+			assert.Equal(t, test.url, doer.requests[0].URL.String())
+			assert.Equal(t, "ProfitAndLoss", resp.Reports[0].ReportType)
+			assert.Len(t, resp.Reports[0].Rows, 4)
+			assert.Equal(t, "Total Income", resp.Reports[0].Rows[1].Rows[1].Cells[0].Value)
+		})
+	}
+}
+
+func TestSummaryReports(t *testing.T) {
+	t.Parallel()
+
+	type fetchFunc func(context.Context, *recordingHTTPDoer) (*xero.ReportResponse, error)
+
+	client := func(doer *recordingHTTPDoer) interface {
+		BankSummary(context.Context, xero.BankSummaryParams, ...xero.CallOption) (*xero.ReportResponse, error)
+		ExecutiveSummary(context.Context, xero.ExecutiveSummaryParams, ...xero.CallOption) (*xero.ReportResponse, error)
+		TrialBalance(context.Context, xero.TrialBalanceParams, ...xero.CallOption) (*xero.ReportResponse, error)
+	} {
+		return xero.HTTPClient(nil).
+			WithBaseURL("http://xero.test").
+			WithHTTPClient(doer)
+	}
+
+	tests := map[string]struct {
+		fixture    string
+		fetch      fetchFunc
+		url        string
+		reportType string
+		rows       int
+		lastRow    []string
+		err        error
+	}{
+		"trial balance": {
+			fixture: "testdata/trial-balance.json",
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).TrialBalance(ctx, xero.TrialBalanceParams{Date: "2024-08-25", PaymentsOnly: true})
+			},
+			url:        "http://xero.test/api.xro/2.0/Reports/TrialBalance?date=2024-08-25&paymentsOnly=true",
+			reportType: "TrialBalance",
+			rows:       7,
+			lastRow:    []string{"Total", "4500.00", "4500.00", "27000.00", "27000.00"},
+		},
+		"trial balance - invalid date": {
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).TrialBalance(ctx, xero.TrialBalanceParams{Date: "today"})
+			},
+			err: xero.ErrInvalidParam,
+		},
+		"bank summary": {
+			fixture: "testdata/bank-summary.json",
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).BankSummary(ctx, xero.BankSummaryParams{FromDate: "2024-08-01", ToDate: "2024-08-25"})
+			},
+			url:        "http://xero.test/api.xro/2.0/Reports/BankSummary?fromDate=2024-08-01&toDate=2024-08-25",
+			reportType: "BankSummary",
+			rows:       2,
+			lastRow:    []string{"Total", "15040.00", "4500.00", "1200.00", "18340.00"},
+		},
+		"bank summary - long range": {
+			fixture: "testdata/bank-summary.json",
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).BankSummary(ctx, xero.BankSummaryParams{FromDate: "2020-01-01", ToDate: "2024-08-25"})
+			},
+			url:        "http://xero.test/api.xro/2.0/Reports/BankSummary?fromDate=2020-01-01&toDate=2024-08-25",
+			reportType: "BankSummary",
+			rows:       2,
+			lastRow:    []string{"Total", "15040.00", "4500.00", "1200.00", "18340.00"},
+		},
+		"bank summary - inverted range": {
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).BankSummary(ctx, xero.BankSummaryParams{FromDate: "2024-08-25", ToDate: "2024-08-01"})
+			},
+			err: xero.ErrInvalidParam,
+		},
+		"executive summary": {
+			fixture: "testdata/executive-summary.json",
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).ExecutiveSummary(ctx, xero.ExecutiveSummaryParams{Date: "2024-08-25"})
+			},
+			url:        "http://xero.test/api.xro/2.0/Reports/ExecutiveSummary?date=2024-08-25",
+			reportType: "ExecutiveSummary",
+			rows:       6,
+			lastRow:    []string{"Term assets to liabilities", "0.00", "0.00", "0.0%"},
+		},
+		"executive summary - invalid date": {
+			fetch: func(ctx context.Context, doer *recordingHTTPDoer) (*xero.ReportResponse, error) {
+				return client(doer).ExecutiveSummary(ctx, xero.ExecutiveSummaryParams{Date: "August"})
+			},
+			err: xero.ErrInvalidParam,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{status: http.StatusOK}
+			if test.fixture != "" {
+				doer.body = fixture(t, test.fixture)
+			}
+
+			resp, err := test.fetch(context.TODO(), doer)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, doer.requests)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.url, doer.requests[0].URL.String())
+
+			report := resp.Reports[0]
+			assert.Equal(t, test.reportType, report.ReportType)
+			assert.Len(t, report.Rows, test.rows)
+
+			lastSection := report.Rows[len(report.Rows)-1].Rows
+			lastRow := make([]string, 0, len(test.lastRow))
+
+			for _, cell := range lastSection[len(lastSection)-1].Cells {
+				lastRow = append(lastRow, cell.Value)
+			}
+
+			assert.Equal(t, test.lastRow, lastRow)
+		})
+	}
+}
+
+func TestAgedReports(t *testing.T) {
+	t.Parallel()
+
+	const contactID = "565acaa9-e7f3-4fbf-80c3-16b081ddae10"
+
+	tests := map[string]struct {
+		fixture    string
+		payables   bool
+		params     xero.AgedReportParams
+		url        string
+		reportType string
+		err        error
+	}{
+		"receivables": {
+			fixture:    "testdata/aged-receivables.json",
+			params:     xero.AgedReportParams{ContactID: contactID, Date: "2024-08-25"},
+			url:        "http://xero.test/api.xro/2.0/Reports/AgedReceivablesByContact?contactID=" + contactID + "&date=2024-08-25",
+			reportType: "AgedReceivablesByContact",
+		},
+		"payables": {
+			fixture:    "testdata/aged-payables.json",
+			payables:   true,
+			params:     xero.AgedReportParams{ContactID: contactID, FromDate: "2024-01-01", ToDate: "2024-08-25"},
+			url:        "http://xero.test/api.xro/2.0/Reports/AgedPayablesByContact?contactID=" + contactID + "&fromDate=2024-01-01&toDate=2024-08-25",
+			reportType: "AgedPayablesByContact",
+		},
+		"missing contact": {
+			params: xero.AgedReportParams{Date: "2024-08-25"},
+			err:    xero.ErrInvalidParam,
+		},
+		"invalid contact": {
+			payables: true,
+			params:   xero.AgedReportParams{ContactID: "Ridgeway University"},
+			err:      xero.ErrInvalidParam,
+		},
+		"inverted range": {
+			params: xero.AgedReportParams{ContactID: contactID, FromDate: "2024-08-25", ToDate: "2024-01-01"},
+			err:    xero.ErrInvalidParam,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				err  error
+				resp *xero.ReportResponse
+			)
+
+			doer := &recordingHTTPDoer{status: http.StatusOK}
+			if test.fixture != "" {
+				doer.body = fixture(t, test.fixture)
+			}
+
+			client := xero.HTTPClient(nil).
+				WithBaseURL("http://xero.test").
+				WithHTTPClient(doer)
+
+			if test.payables {
+				resp, err = client.AgedPayablesByContact(context.TODO(), test.params)
+			} else {
+				resp, err = client.AgedReceivablesByContact(context.TODO(), test.params)
+			}
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, doer.requests)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.url, doer.requests[0].URL.String())
+			assert.Equal(t, test.reportType, resp.Reports[0].ReportType)
+			assert.Equal(t, "Due Date", resp.Reports[0].Rows[0].Cells[2].Value)
+		})
+	}
+}
+
+func fixture(t testing.TB, path string) []byte {
 	t.Helper()
 
 	data, err := os.ReadFile(path)
@@ -260,10 +656,70 @@ func fixture(t *testing.T, path string) []byte {
 	return data
 }
 
-func xeroDTField(t *testing.T, unixSeconds int) xero.DateTimeField {
+func xeroDTField(t *testing.T, unixMillis int64) xero.DateTimeField {
 	t.Helper()
 
-	return xero.DateTimeField{ 
-		time.Unix(int64(unixSeconds), 0),
+	return xero.DateTimeField{
+		Time: time.UnixMilli(unixMillis).UTC(),
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	body := fixture(t, "testdata/reports.json")
+
+	tests := map[string]struct {
+		contentLength int64
+		maxBodySize   int64
+		err           error
+	}{
+		"exactly the limit": {
+			contentLength: -1,
+			maxBodySize:   int64(len(body)),
+			err:           nil,
+		},
+		"one byte over the limit": {
+			contentLength: -1,
+			maxBodySize:   int64(len(body)) - 1,
+			err:           xero.ErrBodyTooLarge,
+		},
+		"Content-Length over the limit": {
+			contentLength: int64(len(body)),
+			maxBodySize:   100,
+			err:           xero.ErrBodyTooLarge,
+		},
+		"limit disabled": {
+			contentLength: int64(len(body)),
+			maxBodySize:   0,
+			err:           nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := xero.HTTPClient(nil).
+				WithHTTPClient(&mockHTTPDoer{
+					mockResponse: &http.Response{
+						Body:          io.NopCloser(bytes.NewReader(body)),
+						ContentLength: test.contentLength,
+						StatusCode:    http.StatusOK,
+					},
+				}).
+				WithMaxBodySize(test.maxBodySize).
+				BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Nil(t, resp)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, resp.Reports, 1)
+		})
 	}
 }
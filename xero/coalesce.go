@@ -0,0 +1,164 @@
+package xero
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// coalescer merges identical concurrent report calls into a single request to Xero, whose result is shared by all
+// the callers waiting for it.
+type coalescer struct {
+	flights map[string]*flight // In-flight calls by key.
+	mu      sync.Mutex         // Guards flights and their callers.
+	nextID  int
+}
+
+// flight is a call shared by one or more callers.
+type flight struct {
+	callers map[int]context.Context // Contexts of the callers still waiting, by ID.
+	ctx     *flightContext
+	done    chan struct{} // Closed when rr and err are set.
+	err     error
+	rr      *ReportResponse
+}
+
+// flightContext is the context of a shared request. It is cancelled only when every caller has given up, and its
+// deadline is the latest of the callers' ones, so that the request does not outlive all of them.
+type flightContext struct {
+	context.Context //nolint:containedctx // Values of the first caller, without its cancellation
+
+	co   *coalescer
+	done chan struct{}
+	err  error
+	f    *flight
+}
+
+func newCoalescer() *coalescer {
+	return &coalescer{
+		flights: make(map[string]*flight),
+		mu:      sync.Mutex{},
+		nextID:  0,
+	}
+}
+
+// do calls fetch, unless an identical call is already in flight, and waits for its result.
+// A cancelled caller stops waiting and gets its context error, the shared request is cancelled only when no caller
+// is left: the last one gets the result of the cancelled request, as if the call had not been shared.
+func (co *coalescer) do(ctx context.Context, key string, fetch func(context.Context) (*ReportResponse, error)) (*ReportResponse, error) {
+	co.mu.Lock()
+
+	f, ok := co.flights[key]
+	if !ok {
+		f = &flight{callers: make(map[int]context.Context), ctx: nil, done: make(chan struct{}), err: nil, rr: nil}
+		f.ctx = &flightContext{Context: context.WithoutCancel(ctx), co: co, done: make(chan struct{}), err: nil, f: f}
+		co.flights[key] = f
+	}
+
+	id := co.nextID
+	co.nextID++
+	f.callers[id] = ctx
+
+	co.mu.Unlock()
+
+	if !ok {
+		go co.run(key, f, fetch)
+	}
+
+	select {
+	case <-f.done:
+		co.leave(key, f, id, nil)
+	case <-ctx.Done():
+		if !co.leave(key, f, id, ctx.Err()) {
+			return nil, ctx.Err() //nolint:wrapcheck // Context errors are returned as they are
+		}
+
+		<-f.done
+	}
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	// Callers get their own copy, as they do from the cache.
+	return served(f.rr, f.rr.Stale), nil
+}
+
+// run makes the shared request and publishes its result.
+func (co *coalescer) run(key string, f *flight, fetch func(context.Context) (*ReportResponse, error)) {
+	rr, err := fetch(f.ctx)
+
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	// Later callers start a new request, rather than getting a result that may be already outdated.
+	if co.flights[key] == f {
+		delete(co.flights, key)
+	}
+
+	f.rr, f.err = rr, err
+	close(f.done)
+}
+
+// leave removes a caller from the flight. If it was the last one and the request is still in flight, the request is
+// cancelled with the caller's context error and leave returns true.
+func (co *coalescer) leave(key string, f *flight, id int, cause error) bool {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	delete(f.callers, id)
+
+	if len(f.callers) > 0 || cause == nil {
+		return false
+	}
+
+	select {
+	case <-f.done:
+		return false
+	default:
+	}
+
+	// Nobody is waiting for the cancelled request, so it must not be joined by new callers.
+	if co.flights[key] == f {
+		delete(co.flights, key)
+	}
+
+	f.ctx.err = cause
+	close(f.ctx.done)
+
+	return true
+}
+
+// Deadline returns the latest deadline of the callers, there is none if any caller has none.
+func (fc *flightContext) Deadline() (time.Time, bool) {
+	fc.co.mu.Lock()
+	defer fc.co.mu.Unlock()
+
+	var latest time.Time
+
+	for _, ctx := range fc.f.callers {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return time.Time{}, false
+		}
+
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	return latest, !latest.IsZero()
+}
+
+// Done is closed when the last caller gives up.
+func (fc *flightContext) Done() <-chan struct{} {
+	return fc.done
+}
+
+// Err returns the context error of the last caller, once it has given up.
+func (fc *flightContext) Err() error {
+	fc.co.mu.Lock()
+	defer fc.co.mu.Unlock()
+
+	return fc.err
+}
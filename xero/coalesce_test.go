@@ -0,0 +1,162 @@
+package xero_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescing(t *testing.T) {
+	t.Parallel()
+
+	tenant1 := xero.ContextWithTenant(context.TODO(), "0f8d3b5e-6a1c-4f3b-9b7e-2d4c8a1e5f60")
+	tenant2 := xero.ContextWithTenant(context.TODO(), "a3c9e7d1-2b4f-4e8a-8c6d-9f1b3e5a7c20")
+
+	tests := map[string]struct {
+		ctxs   []context.Context //nolint:containedctx // Test table
+		params []xero.BalanceSheetParams
+		calls  int32
+	}{
+		"identical calls": {
+			ctxs:   []context.Context{tenant1, tenant1, tenant1, tenant1},
+			params: []xero.BalanceSheetParams{{}, {}, {}, {}},
+			calls:  1,
+		},
+		"different tenants": {
+			ctxs:   []context.Context{tenant1, tenant2, tenant1, tenant2},
+			params: []xero.BalanceSheetParams{{}, {}, {}, {}},
+			calls:  2,
+		},
+		"different parameters": {
+			ctxs:   []context.Context{tenant1, tenant1, tenant1, tenant1},
+			params: []xero.BalanceSheetParams{{}, {Periods: 2}, {}, {Periods: 3}},
+			calls:  3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &blockingHTTPDoer{
+				body:    fixture(t, "testdata/reports.json"),
+				release: make(chan struct{}),
+			}
+
+			client := xero.HTTPClient(nil).WithHTTPClient(doer)
+			results := make([]*xero.ReportResponse, len(test.ctxs))
+
+			var wg sync.WaitGroup
+
+			for i := range test.ctxs {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					rr, err := client.BalanceSheet(test.ctxs[i], test.params[i])
+					assert.NoError(t, err)
+
+					results[i] = rr
+				}()
+			}
+
+			// Every caller must have joined a flight before the shared requests return.
+			assert.Eventually(t, func() bool {
+				return doer.current.Load() == test.calls && client.WaitingCallers() == len(test.ctxs)
+			}, time.Second, time.Millisecond)
+
+			close(doer.release)
+			wg.Wait()
+
+			assert.Equal(t, test.calls, doer.calls.Load())
+
+			for i, rr := range results {
+				assert.Len(t, rr.Reports, 1)
+
+				// Callers get their own copy of the shared result.
+				for _, other := range results[i+1:] {
+					assert.NotSame(t, rr, other)
+				}
+			}
+		})
+	}
+}
+
+func TestCoalescingCancelled(t *testing.T) {
+	t.Parallel()
+
+	t.Run("one of the callers", func(t *testing.T) {
+		t.Parallel()
+
+		doer := &blockingHTTPDoer{
+			body:    fixture(t, "testdata/reports.json"),
+			release: make(chan struct{}),
+		}
+
+		client := xero.HTTPClient(nil).WithHTTPClient(doer)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			rr, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+			assert.NoError(t, err)
+			assert.Len(t, rr.Reports, 1)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return doer.current.Load() == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		rr, err := client.BalanceSheet(ctx, xero.BalanceSheetParams{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, rr)
+
+		// The shared request goes on for the other caller.
+		close(doer.release)
+		<-done
+
+		assert.Equal(t, int32(1), doer.calls.Load())
+	})
+
+	t.Run("every caller", func(t *testing.T) {
+		t.Parallel()
+
+		doer := &blockingHTTPDoer{
+			body:    fixture(t, "testdata/reports.json"),
+			release: make(chan struct{}),
+		}
+
+		client := xero.HTTPClient(nil).WithHTTPClient(doer)
+		ctx, cancel := context.WithCancel(context.TODO())
+
+		go func() {
+			assert.Eventually(t, func() bool {
+				return doer.current.Load() == 1
+			}, time.Second, time.Millisecond)
+
+			cancel()
+		}()
+
+		rr, err := client.BalanceSheet(ctx, xero.BalanceSheetParams{})
+		assert.ErrorIs(t, err, xero.ErrRequestFailure)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, rr)
+
+		// The cancelled request is not joined by later callers.
+		close(doer.release)
+
+		rr, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+		assert.NoError(t, err)
+		assert.Len(t, rr.Reports, 1)
+		assert.Equal(t, int32(2), doer.calls.Load())
+	})
+}
@@ -0,0 +1,171 @@
+package xero
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var ErrInvalidDecimal = errors.New("invalid decimal number") // Error returned when a string is not a decimal number.
+
+// Decimal is an exact decimal number, the value is unscaled / 10^scale.
+// The zero value is 0, and decimals are immutable: every operation returns a new value.
+type Decimal struct {
+	unscaled *big.Int // Nil means zero.
+	scale    int32    // Number of digits after the decimal point.
+}
+
+// ParseDecimal parses a plain decimal number such as "126.70", "-12.5" or "+3".
+// Exponents, thousands separators and spaces are not accepted.
+func ParseDecimal(value string) (Decimal, error) {
+	digits := strings.TrimLeft(value, "+-")
+	if len(value)-len(digits) > 1 {
+		return Decimal{}, errors.Join(ErrInvalidDecimal, errors.New(value)) //nolint:err113 // Detail of ErrInvalidDecimal
+	}
+
+	units, fraction, _ := strings.Cut(digits, ".")
+	if units == "" && fraction == "" || strings.Trim(units+fraction, "0123456789") != "" {
+		return Decimal{}, errors.Join(ErrInvalidDecimal, errors.New(value)) //nolint:err113 // Detail of ErrInvalidDecimal
+	}
+
+	unscaled, _ := new(big.Int).SetString("0"+units+fraction, 10) //nolint:mnd // Base 10
+
+	if strings.HasPrefix(value, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{unscaled: unscaled, scale: int32(len(fraction))}, nil //nolint:gosec // Cell values are short
+}
+
+// NewDecimal returns unscaled / 10^scale, e.g. NewDecimal(12670, 2) is 126.70.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{unscaled: big.NewInt(unscaled), scale: max(scale, 0)}
+}
+
+// Add returns d + other, with the larger of the two scales.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+
+	return Decimal{unscaled: new(big.Int).Add(d.rescaled(scale), other.rescaled(scale)), scale: scale}
+}
+
+// Cmp compares d and other, and returns -1, 0 or +1. Trailing zeros do not matter: 1.50 equals 1.5.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+
+	return d.rescaled(scale).Cmp(other.rescaled(scale))
+}
+
+// IsZero returns whether the decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Round returns d rounded to the given number of digits after the decimal point, halves are rounded away from zero.
+// The result always has exactly that many digits, e.g. 1.5 rounded to 2 places is 1.50.
+func (d Decimal) Round(places int32) Decimal {
+	places = max(places, 0)
+
+	if places >= d.scale {
+		return Decimal{unscaled: d.rescaled(places), scale: places}
+	}
+
+	divisor := pow10(d.scale - places)
+	quotient, remainder := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+
+	// Compare twice the remainder with the divisor to find whether the dropped digits are at least a half.
+	if remainder.Abs(remainder).Lsh(remainder, 1).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+	}
+
+	return Decimal{unscaled: quotient, scale: places}
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// String returns the decimal with all its digits after the decimal point, e.g. "-0.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.scale == 0 {
+		return sign + digits
+	}
+
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	point := len(digits) - int(d.scale)
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Sub returns d - other, with the larger of the two scales.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+// MarshalJSON satisfies json.Marshaler interface, the decimal is written as a JSON number with all its digits.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler interface, it accepts both JSON numbers and strings.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	parsed, err := ParseDecimal(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// int returns the unscaled value, never nil.
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+// rescaled returns the unscaled value for a scale not lower than d's.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.int()
+	}
+
+	factor := pow10(scale - d.scale)
+
+	return factor.Mul(factor, d.int())
+}
+
+// pow10 returns 10^exp.
+func pow10(exp int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil) //nolint:mnd // Base 10
+}
@@ -0,0 +1,83 @@
+package xero_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg  string
+		want string
+		err  error
+	}{
+		"amount":            {arg: "126.70", want: "126.70"},
+		"negative":          {arg: "-12.5", want: "-12.5"},
+		"plus sign":         {arg: "+3", want: "3"},
+		"no units":          {arg: ".05", want: "0.05"},
+		"large":             {arg: "123456789012345678901234.99", want: "123456789012345678901234.99"},
+		"empty":             {arg: "", err: xero.ErrInvalidDecimal},
+		"sign only":         {arg: "-", err: xero.ErrInvalidDecimal},
+		"double sign":       {arg: "--1", err: xero.ErrInvalidDecimal},
+		"percentage":        {arg: "15.4%", err: xero.ErrInvalidDecimal},
+		"float exponent":    {arg: "1e3", err: xero.ErrInvalidDecimal},
+		"two decimal marks": {arg: "1.2.3", err: xero.ErrInvalidDecimal},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := xero.ParseDecimal(test.arg)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got.String())
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	a, _ := xero.ParseDecimal("0.1")
+	b, _ := xero.ParseDecimal("0.20")
+
+	assert.Equal(t, "0.30", a.Add(b).String())
+	assert.Equal(t, "-0.10", a.Sub(b).String())
+	assert.Equal(t, "-0.1", a.Neg().String())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 0, a.Add(b).Cmp(xero.NewDecimal(3, 1)))
+	assert.True(t, a.Sub(a).IsZero())
+	assert.True(t, xero.Decimal{}.IsZero())
+	assert.Equal(t, "1.50", xero.Decimal{}.Add(xero.NewDecimal(150, 2)).String())
+}
+
+func TestDecimalJSON(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Number xero.Decimal
+		String xero.Decimal
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"Number": 126.70, "String": "-0.05"}`), &got))
+	assert.Equal(t, "126.70", got.Number.String())
+	assert.Equal(t, "-0.05", got.String.String())
+
+	out, err := json.Marshal(got)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Number": 126.70, "String": -0.05}`, string(out))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"Number": "n/a"}`), &got), xero.ErrInvalidDecimal)
+}
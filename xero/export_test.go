@@ -0,0 +1,16 @@
+package xero
+
+// WaitingCallers returns how many callers are waiting for an in-flight report call, so that tests can tell when every
+// caller has joined its flight.
+func (c *client) WaitingCallers() int {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+
+	waiting := 0
+
+	for _, f := range c.flights.flights {
+		waiting += len(f.callers)
+	}
+
+	return waiting
+}
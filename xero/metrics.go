@@ -0,0 +1,103 @@
+package xero
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	"github.com/luca-arch/code-drills/metrics"
+)
+
+// outcomes are the values of the outcome label, by error. The first match wins, so wrapping errors come first.
+var outcomes = []struct { //nolint:gochecknoglobals // Read-only list of outcomes
+	err   error
+	label string
+}{
+	{err: ErrInvalidRequest, label: "invalid_request"},
+	{err: ErrUnauthorized, label: "unauthorized"},
+	{err: ErrForbidden, label: "forbidden"},
+	{err: ErrNotFound, label: "not_found"},
+	{err: ErrTooManyRequests, label: "too_many_requests"},
+	{err: ErrXeroDown, label: "xero_down"},
+	{err: ErrUnexpectedStatus, label: "unexpected_status"},
+	{err: ErrBodyTooLarge, label: "body_too_large"},
+	{err: ErrBrokenResponse, label: "broken_response"},
+	{err: ErrInvalidJSON, label: "invalid_json"},
+	{err: ErrInvalidResponse, label: "invalid_response"},
+	{err: ErrTokenFailure, label: "token_failure"},
+	{err: context.Canceled, label: "canceled"},
+	{err: context.DeadlineExceeded, label: "deadline_exceeded"},
+	{err: ErrRequestFailure, label: "request_failure"},
+}
+
+// outcome returns the outcome label of a call, "ok" if err is nil and "other" if it matches no known error.
+func outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	for _, o := range outcomes {
+		if errors.Is(err, o.err) {
+			return o.label
+		}
+	}
+
+	return "other"
+}
+
+// clientMetrics records the calls made to Xero, a nil *clientMetrics records nothing.
+type clientMetrics struct {
+	duration *metrics.Histogram
+	requests *metrics.Counter
+}
+
+// newClientMetrics registers the metrics of the client:
+// - xero_requests_total{endpoint, outcome}, see outcome;
+// - xero_request_duration_seconds{endpoint}, measured by MeasureLatency for the requests that reach Xero;
+// - xero_rate_limit_remaining{tenant, limit}, see RateBudget. Unlimited budgets are left out;
+// - xero_rate_limit_app_minute_remaining, the app-wide minute budget shared by all tenants, left out if unlimited.
+func newClientMetrics(reg *metrics.Registry, limiter func() *rateLimiter) *clientMetrics {
+	reg.GaugeFunc("xero_rate_limit_remaining", "Calls that can still be made before hitting a Xero rate limit.",
+		[]string{"tenant", "limit"},
+		func(set func(float64, ...string)) {
+			for tenantID, budget := range limiter().budgets() {
+				for limit, remaining := range map[string]int{
+					"concurrent": budget.Concurrent,
+					"day":        budget.Day,
+					"minute":     budget.Minute,
+				} {
+					if remaining >= 0 {
+						set(float64(remaining), tenantID, limit)
+					}
+				}
+			}
+		})
+
+	reg.GaugeFunc("xero_rate_limit_app_minute_remaining", "Calls that can still be made in the current minute by all tenants together.",
+		nil,
+		func(set func(float64, ...string)) {
+			if remaining := limiter().appRemaining(); remaining >= 0 {
+				set(float64(remaining))
+			}
+		})
+
+	return &clientMetrics{
+		duration: reg.Histogram("xero_request_duration_seconds", "Duration of the requests made to Xero, until the response headers are received.",
+			metrics.DefaultBuckets, "endpoint"),
+		requests: reg.Counter("xero_requests_total", "Requests made to Xero, by outcome.", "endpoint", "outcome"),
+	}
+}
+
+// latency records the duration of a request sent to Xero.
+func (m *clientMetrics) latency(l Latency) {
+	m.duration.Observe(l.Duration.Seconds(), path.Base(l.Request.URL.Path))
+}
+
+// observe records the outcome of an attempt of the call, including the ones that fail before a request is sent.
+func (m *clientMetrics) observe(cl call, err error) {
+	if m == nil {
+		return
+	}
+
+	m.requests.Inc(path.Base(cl.path), outcome(err))
+}
@@ -0,0 +1,120 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/metrics"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	const tenantID = "70784a63-d24b-46a9-a4db-0e70a274b056"
+
+	responses := []*http.Response{
+		{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(nil))},
+		{StatusCode: http.StatusOK, Header: http.Header{"X-Minlimit-Remaining": {"42"}}, Body: io.NopCloser(bytes.NewReader(fixture(t, "testdata/reports.json")))},
+		{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(bytes.NewReader([]byte("not JSON")))},
+	}
+
+	reg := metrics.NewRegistry()
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(xero.DoerFunc(func(_ *http.Request) (*http.Response, error) {
+			resp := responses[0]
+			responses = responses[1:]
+
+			return resp, nil
+		})).
+		WithMetrics(reg).
+		WithRateLimits(xero.RateLimits{Concurrent: 0, PerDay: 1000, PerMinute: 60, AppPerMinute: 100, MaxWait: 0}).
+		WithRetryPolicy(xero.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}).
+		WithTenant(tenantID)
+
+	_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	_, err = client.TrialBalance(context.TODO(), xero.TrialBalanceParams{})
+	assert.ErrorIs(t, err, xero.ErrInvalidResponse)
+
+	var out bytes.Buffer
+
+	assert.NoError(t, reg.Write(&out))
+
+	for _, line := range []string{
+		`xero_requests_total{endpoint="BalanceSheet",outcome="ok"} 1`,
+		`xero_requests_total{endpoint="BalanceSheet",outcome="xero_down"} 1`,
+		`xero_requests_total{endpoint="TrialBalance",outcome="invalid_response"} 1`,
+		`xero_request_duration_seconds_count{endpoint="BalanceSheet"} 2`,
+		`xero_request_duration_seconds_count{endpoint="TrialBalance"} 1`,
+		`xero_rate_limit_remaining{tenant="` + tenantID + `",limit="day"} 997`,
+		`xero_rate_limit_remaining{tenant="` + tenantID + `",limit="minute"} 41`,
+		`xero_rate_limit_app_minute_remaining 97`,
+	} {
+		assert.Contains(t, out.String(), line+"\n")
+	}
+
+	// Unlimited budgets are left out.
+	assert.NotContains(t, out.String(), `limit="concurrent"`)
+}
+
+// failingSource never provides a token.
+type failingSource struct{}
+
+func (failingSource) Token(context.Context) (*xero.Token, error) {
+	return nil, xero.ErrTokenFailure
+}
+
+func TestMetricsWithoutRequest(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+	doer := &recordingHTTPDoer{body: fixture(t, "testdata/reports.json"), requests: nil, status: http.StatusOK}
+
+	tokenReg := metrics.NewRegistry()
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithMetrics(tokenReg).
+		WithTokenSource(failingSource{}).
+		BankSummary(context.TODO(), xero.BankSummaryParams{})
+	assert.ErrorIs(t, err, xero.ErrTokenFailure)
+
+	var tokenOut bytes.Buffer
+
+	assert.NoError(t, tokenReg.Write(&tokenOut))
+	assert.Contains(t, tokenOut.String(), `xero_requests_total{endpoint="BankSummary",outcome="token_failure"} 1`+"\n")
+	assert.NotContains(t, tokenOut.String(), `xero_request_duration_seconds_count{endpoint="BankSummary"}`)
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithMetrics(reg).
+		WithRateLimits(xero.RateLimits{Concurrent: 0, PerDay: 1, PerMinute: 0, AppPerMinute: 0, MaxWait: time.Millisecond})
+
+	_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{Periods: 2})
+	assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+
+	var out bytes.Buffer
+
+	assert.NoError(t, reg.Write(&out))
+
+	for _, line := range []string{
+		`xero_requests_total{endpoint="BalanceSheet",outcome="ok"} 1`,
+		`xero_requests_total{endpoint="BalanceSheet",outcome="too_many_requests"} 1`,
+		`xero_request_duration_seconds_count{endpoint="BalanceSheet"} 1`, // Only requests sent to Xero are timed.
+	} {
+		assert.Contains(t, out.String(), line+"\n")
+	}
+
+	assert.Len(t, doer.requests, 1)
+}
@@ -0,0 +1,283 @@
+package xero
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultLogBodySize = 4 << 10    // Default number of body bytes captured by LogRequests, 4KiB.
+	Redacted           = "REDACTED" // Replaces the value of redacted headers and body fields.
+)
+
+// DoerFunc adapts a function to the HTTPDoer interface.
+type DoerFunc func(*http.Request) (*http.Response, error)
+
+// Do satisfies the HTTPDoer interface.
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps an HTTPDoer to add behaviour to every request, such as logging or extra headers.
+// Middlewares must not modify the request they receive, but a clone of it.
+type Middleware func(next HTTPDoer) HTTPDoer
+
+// Chain wraps doer with the middlewares. They run in the given order: the first middleware sees the request first and
+// the response last.
+func Chain(doer HTTPDoer, middlewares ...Middleware) HTTPDoer { //nolint:ireturn // Middlewares are only known by interface
+	for _, mw := range slices.Backward(middlewares) {
+		doer = mw(doer)
+	}
+
+	return doer
+}
+
+// AcceptGzip asks for gzip compressed responses and decompresses them, so that the next middlewares and the client
+// only see plain bodies. The client's maximum body size applies to the decompressed body.
+func AcceptGzip() Middleware {
+	return func(next HTTPDoer) HTTPDoer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Accept-Encoding") == "" {
+				req = req.Clone(req.Context())
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+
+			resp, err := next.Do(req)
+			if err != nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+				return resp, err //nolint:wrapcheck // Errors of the next HTTPDoer are returned as they are
+			}
+
+			zr, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				resp.Body.Close()
+
+				return nil, errors.Join(ErrInvalidResponse, err)
+			}
+
+			resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+			resp.ContentLength = -1
+			resp.Uncompressed = true
+			resp.Header.Del("Content-Encoding")
+			resp.Header.Del("Content-Length")
+
+			return resp, nil
+		})
+	}
+}
+
+// gzipBody decompresses a response body, closing it closes the compressed body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipBody) Close() error {
+	return errors.Join(g.Reader.Close(), g.body.Close())
+}
+
+// DefaultHeaders sets the given headers on every request that does not already have them.
+func DefaultHeaders(header http.Header) Middleware {
+	return func(next HTTPDoer) HTTPDoer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			clone := req.Clone(req.Context())
+
+			for name, values := range header {
+				if clone.Header.Get(name) == "" {
+					clone.Header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+				}
+			}
+
+			return next.Do(clone) //nolint:wrapcheck // Errors of the next HTTPDoer are returned as they are
+		})
+	}
+}
+
+// UserAgent sets the User-Agent header of every request that does not already have one.
+func UserAgent(userAgent string) Middleware {
+	return DefaultHeaders(http.Header{"User-Agent": []string{userAgent}})
+}
+
+// Latency is the duration of a single request, as measured by MeasureLatency.
+type Latency struct {
+	Duration   time.Duration
+	Err        error // Error returned by the next HTTPDoer, if any.
+	Request    *http.Request
+	StatusCode int // Zero when Err is not nil.
+}
+
+// MeasureLatency passes the duration of every request to observe. Requests are measured until the response headers
+// are received, reading the body is not accounted for.
+func MeasureLatency(observe func(Latency)) Middleware {
+	return func(next HTTPDoer) HTTPDoer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			resp, err := next.Do(req)
+
+			latency := Latency{Duration: time.Since(start), Err: err, Request: req, StatusCode: 0}
+			if err == nil {
+				latency.StatusCode = resp.StatusCode
+			}
+
+			observe(latency)
+
+			return resp, err //nolint:wrapcheck // Errors of the next HTTPDoer are returned as they are
+		})
+	}
+}
+
+// LogOptions configures LogRequests.
+type LogOptions struct {
+	Bodies        bool     // Whether to log the request and response bodies.
+	MaxBodySize   int      // Number of body bytes logged, defaults to DefaultLogBodySize.
+	RedactFields  []string // Extra JSON and form fields replaced by REDACTED, OAuth tokens and secrets always are.
+	RedactHeaders []string // Extra headers replaced by REDACTED, credentials and cookies always are.
+}
+
+// sensitiveHeaders are the headers carrying credentials, they are always redacted from the logs.
+var sensitiveHeaders = []string{ //nolint:gochecknoglobals // Read-only list of headers
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"Xero-User-Id",
+}
+
+// sensitiveFields are the JSON and form fields carrying OAuth tokens and secrets, they are always redacted from the
+// logged bodies.
+var sensitiveFields = []string{"access_token", "client_secret", "id_token", "refresh_token"} //nolint:gochecknoglobals // Read-only list of fields
+
+// SensitiveHeaders returns the headers carrying credentials, which are always redacted from the logs.
+func SensitiveHeaders() []string {
+	return slices.Clone(sensitiveHeaders)
+}
+
+// SensitiveFields returns the JSON and form fields carrying OAuth tokens and secrets, which are always redacted from
+// the logged bodies.
+func SensitiveFields() []string {
+	return slices.Clone(sensitiveFields)
+}
+
+// LogRequests logs every request and its response at debug level, redacting the credentials.
+// Response bodies are logged once the body is closed, only the bytes that were read are captured.
+func LogRequests(logger *slog.Logger, opts LogOptions) Middleware {
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultLogBodySize
+	}
+
+	headers := slices.Concat(sensitiveHeaders, opts.RedactHeaders)
+	redactions := make([]redaction, 0, 2*(len(sensitiveFields)+len(opts.RedactFields))) //nolint:mnd // One for JSON, one for forms
+
+	for _, field := range slices.Concat(sensitiveFields, opts.RedactFields) {
+		name := regexp.QuoteMeta(field)
+
+		redactions = append(redactions,
+			redaction{re: regexp.MustCompile(`("` + name + `"\s*:\s*)"(?:[^"\\]|\\.)*"?`), repl: `${1}"` + Redacted + `"`},
+			redaction{re: regexp.MustCompile(`(^|&)(` + name + `=)[^&]*`), repl: `${1}${2}` + Redacted},
+		)
+	}
+
+	redactBody := func(body []byte) string {
+		s := string(body)
+
+		for _, r := range redactions {
+			s = r.re.ReplaceAllString(s, r.repl)
+		}
+
+		return s
+	}
+
+	return func(next HTTPDoer) HTTPDoer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			attrs := []any{"method", req.Method, "url", req.URL.Redacted(), "header", redactHeader(req.Header, headers)}
+
+			if opts.Bodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					captured, _ := io.ReadAll(io.LimitReader(body, int64(opts.MaxBodySize)))
+					body.Close()
+
+					attrs = append(attrs, "body", redactBody(captured))
+				}
+			}
+
+			logger.Debug("Xero HTTP request", attrs...)
+
+			resp, err := next.Do(req)
+			if err != nil {
+				logger.Debug("Xero HTTP request failed", "method", req.Method, "url", req.URL.Redacted(), "err", err)
+
+				return resp, err //nolint:wrapcheck // Errors of the next HTTPDoer are returned as they are
+			}
+
+			logger.Debug("Xero HTTP response",
+				"method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "header", redactHeader(resp.Header, headers))
+
+			if opts.Bodies {
+				resp.Body = &capturingBody{
+					ReadCloser: resp.Body,
+					captured:   bytes.Buffer{},
+					max:        opts.MaxBodySize,
+					once:       sync.Once{},
+					onClose: func(captured []byte) {
+						logger.Debug("Xero HTTP response body", "method", req.Method, "url", req.URL.Redacted(), "body", redactBody(captured))
+					},
+				}
+			}
+
+			return resp, nil
+		})
+	}
+}
+
+// redaction replaces the value of a field in logged bodies.
+type redaction struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// redactHeader returns a copy of the header with the values of the given headers replaced by REDACTED.
+func redactHeader(header http.Header, names []string) http.Header {
+	clone := header.Clone()
+
+	for name := range clone {
+		if slices.ContainsFunc(names, func(sensitive string) bool { return strings.EqualFold(name, sensitive) }) {
+			clone[name] = []string{Redacted}
+		}
+	}
+
+	return clone
+}
+
+// capturingBody keeps up to max bytes of what is read from a response body, and passes them to onClose.
+type capturingBody struct {
+	io.ReadCloser
+	captured bytes.Buffer
+	max      int
+	once     sync.Once
+	onClose  func([]byte)
+}
+
+func (c *capturingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+
+	if room := c.max - c.captured.Len(); room > 0 {
+		c.captured.Write(p[:min(n, room)])
+	}
+
+	return n, err //nolint:wrapcheck // io.Reader errors, such as io.EOF, are returned as they are
+}
+
+func (c *capturingBody) Close() error {
+	c.once.Do(func() { c.onClose(c.captured.Bytes()) })
+
+	return c.ReadCloser.Close() //nolint:wrapcheck // Errors of the response body are returned as they are
+}
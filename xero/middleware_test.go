@@ -0,0 +1,236 @@
+package xero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// tracing returns a middleware that appends its name to trace before and after the next HTTPDoer.
+func tracing(name string, trace *[]string) xero.Middleware {
+	return func(next xero.HTTPDoer) xero.HTTPDoer {
+		return xero.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			*trace = append(*trace, "> "+name)
+			resp, err := next.Do(req)
+			*trace = append(*trace, "< "+name)
+
+			return resp, err
+		})
+	}
+}
+
+func TestMiddlewaresOrder(t *testing.T) {
+	t.Parallel()
+
+	var trace []string
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(&recordingHTTPDoer{body: fixture(t, "testdata/reports.json"), requests: nil, status: http.StatusOK}).
+		WithMiddlewares(tracing("a", &trace), tracing("b", &trace)).
+		WithMiddlewares(tracing("c", &trace)).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"> a", "> b", "> c", "< c", "< b", "< a"}, trace)
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingHTTPDoer{body: fixture(t, "testdata/reports.json"), requests: nil, status: http.StatusOK}
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithMiddlewares(
+			xero.UserAgent("code-drills/1.0"),
+			xero.DefaultHeaders(http.Header{"Accept": {"text/plain"}, "X-Team": {"finance"}}),
+		).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	header := doer.requests[0].Header
+	assert.Equal(t, "code-drills/1.0", header.Get("User-Agent"))
+	assert.Equal(t, "finance", header.Get("X-Team"))
+	assert.Equal(t, "application/json", header.Get("Accept")) // Headers of the client are kept.
+}
+
+func TestAcceptGzip(t *testing.T) {
+	t.Parallel()
+
+	var compressed bytes.Buffer
+
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write(fixture(t, "testdata/reports.json"))
+	zw.Close()
+
+	tests := map[string]struct {
+		body     []byte
+		encoding string
+		err      error
+	}{
+		"gzip response": {
+			body:     compressed.Bytes(),
+			encoding: "gzip",
+			err:      nil,
+		},
+		"plain response": {
+			body:     fixture(t, "testdata/reports.json"),
+			encoding: "",
+			err:      nil,
+		},
+		"error - invalid gzip": {
+			body:     []byte("not gzip"),
+			encoding: "gzip",
+			err:      xero.ErrInvalidResponse,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var accept string
+
+			rr, err := xero.HTTPClient(nil).
+				WithHTTPClient(xero.DoerFunc(func(req *http.Request) (*http.Response, error) {
+					accept = req.Header.Get("Accept-Encoding")
+
+					return &http.Response{
+						Body:          io.NopCloser(bytes.NewReader(test.body)),
+						ContentLength: int64(len(test.body)),
+						Header:        http.Header{"Content-Encoding": {test.encoding}},
+						StatusCode:    http.StatusOK,
+					}, nil
+				})).
+				WithMiddlewares(xero.AcceptGzip()).
+				BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+			assert.Equal(t, "gzip", accept)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, rr.Reports, 1)
+		})
+	}
+}
+
+func TestMeasureLatency(t *testing.T) {
+	t.Parallel()
+
+	var latencies []xero.Latency
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(&recordingHTTPDoer{body: fixture(t, "testdata/error.json"), requests: nil, status: http.StatusServiceUnavailable}).
+		WithMiddlewares(xero.MeasureLatency(func(l xero.Latency) { latencies = append(latencies, l) })).
+		WithRetryPolicy(xero.RetryPolicy{MaxAttempts: 2, BaseDelay: 0, MaxDelay: 0}).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.ErrorIs(t, err, xero.ErrXeroDown)
+
+	// Every attempt is measured.
+	assert.Len(t, latencies, 2)
+
+	for _, l := range latencies {
+		assert.Equal(t, http.StatusServiceUnavailable, l.StatusCode)
+		assert.NoError(t, l.Err)
+		assert.Equal(t, "/api.xro/2.0/Reports/BalanceSheet", l.Request.URL.Path)
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts     xero.LogOptions
+		contains []string
+	}{
+		"without bodies": {
+			opts:     xero.LogOptions{Bodies: false, MaxBodySize: 0, RedactFields: nil, RedactHeaders: nil},
+			contains: []string{`msg="Xero HTTP request"`, "status=200", "Authorization:[REDACTED]", "Set-Cookie:[REDACTED]"},
+		},
+		"with bodies": {
+			opts: xero.LogOptions{Bodies: true, MaxBodySize: 0, RedactFields: nil, RedactHeaders: nil},
+			contains: []string{
+				`body="grant_type=refresh_token&refresh_token=REDACTED"`,
+				`\"access_token\":\"REDACTED\",\"expires_in\":1800`,
+			},
+		},
+		"with truncated bodies": {
+			opts:     xero.LogOptions{Bodies: true, MaxBodySize: 24, RedactFields: nil, RedactHeaders: nil},
+			contains: []string{`body="{\"access_token\":\"REDACTED\""`},
+		},
+		"extra redactions": {
+			opts:     xero.LogOptions{Bodies: true, MaxBodySize: 0, RedactFields: []string{"grant_type"}, RedactHeaders: []string{"X-Team"}},
+			contains: []string{"X-Team:[REDACTED]", "grant_type=REDACTED&refresh_token=REDACTED"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{AddSource: false, Level: slog.LevelDebug, ReplaceAttr: nil}))
+
+			doer := xero.Chain(tokenDoer{}, xero.LogRequests(logger, test.opts))
+
+			req, _ := http.NewRequestWithContext(context.TODO(), http.MethodPost, "https://identity.xero.com/connect/token",
+				strings.NewReader("grant_type=refresh_token&refresh_token=rt-123"))
+			req.Header.Set("Authorization", "Basic c2VjcmV0")
+			req.Header.Set("X-Team", "finance")
+
+			resp, err := doer.Do(req)
+			assert.NoError(t, err)
+
+			// The caller gets the response unchanged.
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			assert.Contains(t, string(body), "at-456")
+
+			for _, secret := range []string{"c2VjcmV0", "rt-123", "at-456", "session=abc"} {
+				assert.NotContains(t, logs.String(), secret)
+			}
+
+			for _, s := range test.contains {
+				assert.Contains(t, logs.String(), s)
+			}
+		})
+	}
+}
+
+// tokenDoer replies with an OAuth token response.
+type tokenDoer struct{}
+
+func (tokenDoer) Do(_ *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Body:       io.NopCloser(strings.NewReader(`{"access_token":"at-456","expires_in":1800}`)),
+		Header:     http.Header{"Set-Cookie": {"session=abc"}},
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func TestSensitiveLists(t *testing.T) {
+	t.Parallel()
+
+	headers := xero.SensitiveHeaders()
+	headers[0] = "X-Other"
+
+	fields := xero.SensitiveFields()
+	fields[0] = "other"
+
+	// Callers get their own copy, the redacted lists cannot be changed.
+	assert.Contains(t, xero.SensitiveHeaders(), "Authorization")
+	assert.Contains(t, xero.SensitiveFields(), "access_token")
+}
@@ -0,0 +1,146 @@
+package xero
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const defaultMinorDigits = 2 // Digits after the decimal point for most ISO 4217 currencies.
+
+var ErrCurrencyMismatch = errors.New("money amounts in different currencies") // Error returned when combining two currencies.
+
+// minorDigits lists the ISO 4217 currencies whose minor unit is not the cent.
+var minorDigits = map[string]int32{ //nolint:gochecknoglobals // Read-only lookup table
+	"BHD": 3, "CLP": 0, "IQD": 3, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3,
+	"LYD": 3, "OMR": 3, "PYG": 0, "TND": 3, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+}
+
+// Money is an exact amount in the organisation's currency.
+// An empty Currency stands for an unknown one, it is compatible with any other currency.
+type Money struct {
+	Amount   Decimal
+	Currency string // ISO 4217 code, e.g. NZD.
+}
+
+// ParseMoney parses a numeric value as formatted by Xero, such as "126.70", "-1,500.00" or "(1,500.00)",
+// where brackets denote a negative amount.
+func ParseMoney(value, currency string) (Money, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+
+	if inner, ok := strings.CutPrefix(value, "("); ok {
+		if inner, ok = strings.CutSuffix(inner, ")"); !ok || strings.ContainsAny(inner, "+-") {
+			return Money{}, errors.Join(ErrInvalidDecimal, errors.New(value)) //nolint:err113 // Detail of ErrInvalidDecimal
+		}
+
+		value = "-" + inner
+	}
+
+	amount, err := ParseDecimal(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Add returns m + other, it fails with ErrCurrencyMismatch if the currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.currency(other)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: currency}, nil
+}
+
+// Cmp compares m and other and returns -1, 0 or +1, it fails with ErrCurrencyMismatch if the currencies differ.
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.currency(other); err != nil {
+		return 0, err
+	}
+
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// MinorUnits returns the amount rounded to the currency's minor unit, e.g. 126.70 NZD is 12670 cents.
+func (m Money) MinorUnits() *big.Int {
+	digits := m.minorDigits()
+
+	return new(big.Int).Set(m.Amount.Round(digits).int())
+}
+
+// Round returns m rounded to the given number of digits after the decimal point, halves are rounded away from zero.
+func (m Money) Round(places int32) Money {
+	return Money{Amount: m.Amount.Round(places), Currency: m.Currency}
+}
+
+// String returns the amount followed by the currency, if any.
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+
+	return m.Amount.String() + " " + m.Currency
+}
+
+// Sub returns m - other, it fails with ErrCurrencyMismatch if the currencies differ.
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(Money{Amount: other.Amount.Neg(), Currency: other.Currency})
+}
+
+// moneyJSON is the JSON representation of Money.
+type moneyJSON struct {
+	Amount     string   `json:"amount"`
+	Currency   string   `json:"currency,omitempty"`
+	MinorUnits *big.Int `json:"minorUnits"`
+}
+
+// MarshalJSON satisfies json.Marshaler interface, e.g. {"amount":"126.70","currency":"NZD","minorUnits":12670}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{ //nolint:wrapcheck // Marshalling strings and integers does not fail
+		Amount:     m.Amount.String(),
+		Currency:   m.Currency,
+		MinorUnits: m.MinorUnits(),
+	})
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler interface, the amount is read from its string and minor units are ignored.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.Join(ErrInvalidDecimal, err)
+	}
+
+	parsed, err := ParseMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// currency returns the currency of an operation between m and other.
+func (m Money) currency(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency || other.Currency == "":
+		return m.Currency, nil
+	case m.Currency == "":
+		return other.Currency, nil
+	default:
+		return "", errors.Join(ErrCurrencyMismatch, errors.New(m.Currency+" and "+other.Currency)) //nolint:err113 // Detail of ErrCurrencyMismatch
+	}
+}
+
+// minorDigits returns the number of digits of the currency's minor unit.
+func (m Money) minorDigits() int32 {
+	if digits, ok := minorDigits[strings.ToUpper(m.Currency)]; ok {
+		return digits
+	}
+
+	return defaultMinorDigits
+}
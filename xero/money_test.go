@@ -0,0 +1,146 @@
+package xero_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg  string
+		want string
+		err  error
+	}{
+		"plain":                {arg: "126.70", want: "126.70"},
+		"negative":             {arg: "-12.5", want: "-12.5"},
+		"thousands separators": {arg: "1,234,567.89", want: "1234567.89"},
+		"bracketed negative":   {arg: "(1,500.00)", want: "-1500.00"},
+		"surrounding spaces":   {arg: " 3.00 ", want: "3.00"},
+		"unbalanced bracket":   {arg: "(1500.00", err: xero.ErrInvalidDecimal},
+		"bracketed and signed": {arg: "(-1500.00)", err: xero.ErrInvalidDecimal},
+		"text":                 {arg: "Total Assets", err: xero.ErrInvalidDecimal},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := xero.ParseMoney(test.arg, "NZD")
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got.Amount.String())
+			assert.Equal(t, "NZD", got.Currency)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	t.Parallel()
+
+	a, _ := xero.ParseMoney("0.10", "NZD")
+	b, _ := xero.ParseMoney("0.2", "NZD")
+	usd, _ := xero.ParseMoney("1.00", "USD")
+
+	sum, err := a.Add(b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.30 NZD", sum.String())
+
+	diff, err := a.Sub(b)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.10 NZD", diff.String())
+
+	cmp, err := sum.Cmp(xero.Money{Amount: xero.NewDecimal(3, 1), Currency: "NZD"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+
+	_, err = a.Add(usd)
+	assert.ErrorIs(t, err, xero.ErrCurrencyMismatch)
+
+	_, err = a.Cmp(usd)
+	assert.ErrorIs(t, err, xero.ErrCurrencyMismatch)
+
+	unknown, err := xero.Money{Amount: xero.NewDecimal(1, 0), Currency: ""}.Add(usd)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2.00 USD", unknown.String())
+}
+
+func TestMoneyRound(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg    string
+		places int32
+		want   string
+	}{
+		"half up":          {arg: "2.345", places: 2, want: "2.35"},
+		"below half":       {arg: "2.344", places: 2, want: "2.34"},
+		"negative half":    {arg: "-2.345", places: 2, want: "-2.35"},
+		"carry":            {arg: "9.995", places: 2, want: "10.00"},
+		"pads zeros":       {arg: "1.5", places: 2, want: "1.50"},
+		"to units":         {arg: "0.5", places: 0, want: "1"},
+		"negative places":  {arg: "12.5", places: -1, want: "13"},
+		"small to zero":    {arg: "0.004", places: 2, want: "0.00"},
+		"negative to zero": {arg: "-0.004", places: 2, want: "0.00"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := xero.ParseMoney(test.arg, "")
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, m.Round(test.places).Amount.String())
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		amount   string
+		currency string
+		want     string
+	}{
+		"cents":         {amount: "126.7", currency: "NZD", want: `{"amount":"126.7","currency":"NZD","minorUnits":12670}`},
+		"no minor unit": {amount: "1500", currency: "JPY", want: `{"amount":"1500","currency":"JPY","minorUnits":1500}`},
+		"three digits":  {amount: "-1.2345", currency: "KWD", want: `{"amount":"-1.2345","currency":"KWD","minorUnits":-1235}`},
+		"no currency":   {amount: "0.05", currency: "", want: `{"amount":"0.05","minorUnits":5}`},
+		"thousands":     {amount: "(1,234.56)", currency: "AUD", want: `{"amount":"-1234.56","currency":"AUD","minorUnits":-123456}`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := xero.ParseMoney(test.amount, test.currency)
+			assert.NoError(t, err)
+
+			data, err := json.Marshal(m)
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.want, string(data))
+
+			var decoded xero.Money
+
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, m.String(), decoded.String())
+		})
+	}
+}
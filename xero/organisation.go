@@ -0,0 +1,41 @@
+package xero
+
+import (
+	"context"
+	"errors"
+)
+
+// Organisation is a Xero organisation, only the fields used by this service are decoded.
+// See https://developer.xero.com/documentation/api/accounting/organisation
+type Organisation struct {
+	OrganisationID string `description:"Organisation UUID" json:"OrganisationID"`
+	Name           string `description:"Organisation human-readable name" json:"Name"`
+	BaseCurrency   string `description:"ISO 4217 code of the organisation's currency (NZD, USD, ...)" json:"BaseCurrency"`
+}
+
+// organisationEnvelope is the body of the Organisation endpoint, where the organisation comes along with the Status.
+type organisationEnvelope struct {
+	Response
+
+	Organisations []Organisation `json:"Organisations"`
+}
+
+// Organisation invokes the Organisation endpoint and returns the organisation of the tenant.
+// See https://developer.xero.com/documentation/api/accounting/organisation#get-organisation
+func (c *client) Organisation(ctx context.Context, opts ...CallOption) (*Organisation, error) {
+	tenantID, err := c.tenant(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := fetch[organisationEnvelope](ctx, c, call{path: "/api.xro/2.0/Organisation", query: nil, tenantID: tenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Organisations) == 0 {
+		return nil, errors.Join(ErrInvalidJSON, errors.New("no organisation in response")) //nolint:err113 // Detail of ErrInvalidJSON
+	}
+
+	return &envelope.Organisations[0], nil
+}
@@ -0,0 +1,73 @@
+package xero_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganisation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		body   []byte
+		status int
+		err    error
+	}{
+		"success": {
+			body:   fixture(t, "testdata/organisation.json"),
+			status: http.StatusOK,
+		},
+		"no organisation": {
+			body:   []byte(`{"Status":"OK","Organisations":[]}`),
+			status: http.StatusOK,
+			err:    xero.ErrInvalidJSON,
+		},
+		"broken response": {
+			body:   fixture(t, "testdata/error.json"),
+			status: http.StatusOK,
+			err:    xero.ErrBrokenResponse,
+		},
+		"forbidden": {
+			body:   nil,
+			status: http.StatusForbidden,
+			err:    xero.ErrForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{
+				body:   test.body,
+				status: test.status,
+			}
+
+			org, err := xero.HTTPClient(nil).
+				WithBaseURL("http://xero.test").
+				WithHTTPClient(doer).
+				WithTenant("70784a63-d24b-46a9-a4db-0e70a274b056").
+				Organisation(context.TODO())
+
+			assert.Equal(t, "http://xero.test/api.xro/2.0/Organisation", doer.requests[0].URL.String())
+			assert.Equal(t, "70784a63-d24b-46a9-a4db-0e70a274b056", doer.requests[0].Header.Get("Xero-tenant-id"))
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &xero.Organisation{
+				OrganisationID: "b2c885a9-4bb9-4a00-9b6e-6c2bf60b1a2b",
+				Name:           "Maple Florists Ltd",
+				BaseCurrency:   "NZD",
+			}, org)
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package xero
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	MaxDateRangeDays = 365 // Maximum number of days between fromDate and toDate of the Profit and Loss report.
+	MaxPeriods       = 11  // Maximum number of periods Xero allows to compare.
+	MinPeriods       = 1   // Minimum number of periods Xero allows to compare.
+)
+
+var (
+	ErrInvalidParam = errors.New("invalid report parameter") // Error returned when a parameter is rejected before sending the request.
+
+	xeroUUIDFormat = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`) // Format of Xero's identifiers.
+)
+
+// Timeframe is the period size reports are compared to.
+type Timeframe string
+
+const (
+	TimeframeMonth   Timeframe = "MONTH"
+	TimeframeQuarter Timeframe = "QUARTER"
+	TimeframeYear    Timeframe = "YEAR"
+)
+
+// Valid returns whether the timeframe is one of the values accepted by Xero.
+func (t Timeframe) Valid() bool {
+	switch t {
+	case TimeframeMonth, TimeframeQuarter, TimeframeYear:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParamError is returned when a report parameter does not satisfy Xero's rules.
+// It matches ErrInvalidParam through errors.Is.
+type ParamError struct {
+	Param  string // Query parameter name, as sent to Xero.
+	Value  string // Offending value.
+	Reason string // Human-readable explanation.
+}
+
+// Error satisfies the error interface.
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid parameter %q (%q): %s", e.Param, e.Value, e.Reason)
+}
+
+// Unwrap returns ErrInvalidParam.
+func (e *ParamError) Unwrap() error {
+	return ErrInvalidParam
+}
+
+// BalanceSheetParams contains the query parameters of the Reports BalanceSheet endpoint.
+// Zero values are omitted from the query string and Xero applies its own defaults.
+// See https://developer.xero.com/documentation/api/accounting/reports#balance-sheet
+type BalanceSheetParams struct {
+	Date              string    // Report date in YYYY-MM-DD format (e.g. 2024-08-25).
+	Periods           int       // Number of periods to compare (integer between 1 and 11).
+	Timeframe         Timeframe // Period size to compare to.
+	TrackingOptionID1 string    // Filter by this tracking option.
+	TrackingOptionID2 string    // Filter by a second tracking option, requires TrackingOptionID1.
+	StandardLayout    bool      // Do not apply custom report layouts.
+	PaymentsOnly      bool      // Return cash transactions only.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p BalanceSheetParams) Validate() error {
+	if err := validateDate("date", p.Date); err != nil {
+		return err
+	}
+
+	if err := validatePeriods(p.Periods); err != nil {
+		return err
+	}
+
+	if err := validateTimeframe(p.Timeframe); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingOptionID1", p.TrackingOptionID1); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingOptionID2", p.TrackingOptionID2); err != nil {
+		return err
+	}
+
+	if p.TrackingOptionID2 != "" && p.TrackingOptionID1 == "" {
+		return &ParamError{
+			Param:  "trackingOptionID2",
+			Value:  p.TrackingOptionID2,
+			Reason: "requires trackingOptionID1",
+		}
+	}
+
+	return nil
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p BalanceSheetParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "date", p.Date)
+	setInt(query, "periods", p.Periods)
+	setString(query, "timeframe", string(p.Timeframe))
+	setString(query, "trackingOptionID1", p.TrackingOptionID1)
+	setString(query, "trackingOptionID2", p.TrackingOptionID2)
+	setBool(query, "standardLayout", p.StandardLayout)
+	setBool(query, "paymentsOnly", p.PaymentsOnly)
+
+	return query
+}
+
+// ProfitAndLossParams contains the query parameters of the Reports ProfitAndLoss endpoint.
+// Zero values are omitted from the query string and Xero applies its own defaults.
+// See https://developer.xero.com/documentation/api/accounting/reports#profit-and-loss
+type ProfitAndLossParams struct {
+	FromDate            string    // Start of the report in YYYY-MM-DD format.
+	ToDate              string    // End of the report in YYYY-MM-DD format, no more than 365 days after FromDate.
+	Periods             int       // Number of periods to compare (integer between 1 and 11).
+	Timeframe           Timeframe // Period size to compare to.
+	TrackingCategoryID  string    // Show figures for each option of this tracking category.
+	TrackingOptionID    string    // Filter by this option of TrackingCategoryID.
+	TrackingCategoryID2 string    // Show figures for each option of a second tracking category, requires TrackingCategoryID.
+	TrackingOptionID2   string    // Filter by this option of TrackingCategoryID2.
+	StandardLayout      bool      // Do not apply custom report layouts.
+	PaymentsOnly        bool      // Return cash transactions only.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p ProfitAndLossParams) Validate() error {
+	if err := validateDateRange(p.FromDate, p.ToDate, MaxDateRangeDays); err != nil {
+		return err
+	}
+
+	if err := validatePeriods(p.Periods); err != nil {
+		return err
+	}
+
+	if err := validateTimeframe(p.Timeframe); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingCategoryID", p.TrackingCategoryID); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingOptionID", p.TrackingOptionID); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingCategoryID2", p.TrackingCategoryID2); err != nil {
+		return err
+	}
+
+	if err := validateID("trackingOptionID2", p.TrackingOptionID2); err != nil {
+		return err
+	}
+
+	if p.TrackingOptionID != "" && p.TrackingCategoryID == "" {
+		return &ParamError{
+			Param:  "trackingOptionID",
+			Value:  p.TrackingOptionID,
+			Reason: "requires trackingCategoryID",
+		}
+	}
+
+	if p.TrackingCategoryID2 != "" && p.TrackingCategoryID == "" {
+		return &ParamError{
+			Param:  "trackingCategoryID2",
+			Value:  p.TrackingCategoryID2,
+			Reason: "requires trackingCategoryID",
+		}
+	}
+
+	if p.TrackingOptionID2 != "" && p.TrackingCategoryID2 == "" {
+		return &ParamError{
+			Param:  "trackingOptionID2",
+			Value:  p.TrackingOptionID2,
+			Reason: "requires trackingCategoryID2",
+		}
+	}
+
+	return nil
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p ProfitAndLossParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "fromDate", p.FromDate)
+	setString(query, "toDate", p.ToDate)
+	setInt(query, "periods", p.Periods)
+	setString(query, "timeframe", string(p.Timeframe))
+	setString(query, "trackingCategoryID", p.TrackingCategoryID)
+	setString(query, "trackingOptionID", p.TrackingOptionID)
+	setString(query, "trackingCategoryID2", p.TrackingCategoryID2)
+	setString(query, "trackingOptionID2", p.TrackingOptionID2)
+	setBool(query, "standardLayout", p.StandardLayout)
+	setBool(query, "paymentsOnly", p.PaymentsOnly)
+
+	return query
+}
+
+// TrialBalanceParams contains the query parameters of the Reports TrialBalance endpoint.
+// See https://developer.xero.com/documentation/api/accounting/reports#trial-balance
+type TrialBalanceParams struct {
+	Date         string // Report date in YYYY-MM-DD format.
+	PaymentsOnly bool   // Return cash transactions only.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p TrialBalanceParams) Validate() error {
+	return validateDate("date", p.Date)
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p TrialBalanceParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "date", p.Date)
+	setBool(query, "paymentsOnly", p.PaymentsOnly)
+
+	return query
+}
+
+// BankSummaryParams contains the query parameters of the Reports BankSummary endpoint.
+// See https://developer.xero.com/documentation/api/accounting/reports#bank-summary
+type BankSummaryParams struct {
+	FromDate string // Start of the report in YYYY-MM-DD format.
+	ToDate   string // End of the report in YYYY-MM-DD format.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p BankSummaryParams) Validate() error {
+	return validateDateRange(p.FromDate, p.ToDate, 0)
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p BankSummaryParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "fromDate", p.FromDate)
+	setString(query, "toDate", p.ToDate)
+
+	return query
+}
+
+// ExecutiveSummaryParams contains the query parameters of the Reports ExecutiveSummary endpoint.
+// See https://developer.xero.com/documentation/api/accounting/reports#executive-summary
+type ExecutiveSummaryParams struct {
+	Date string // Report date in YYYY-MM-DD format, the report covers the month of this date.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p ExecutiveSummaryParams) Validate() error {
+	return validateDate("date", p.Date)
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p ExecutiveSummaryParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "date", p.Date)
+
+	return query
+}
+
+// AgedReportParams contains the query parameters of the Reports AgedReceivablesByContact and AgedPayablesByContact endpoints.
+// See https://developer.xero.com/documentation/api/accounting/reports#aged-receivables-by-contact
+type AgedReportParams struct {
+	ContactID string // Required, the contact to age invoices for.
+	Date      string // Shows payments up to this date in YYYY-MM-DD format, defaults to the end of the month.
+	FromDate  string // Show invoices from this date in YYYY-MM-DD format.
+	ToDate    string // Show invoices up to this date in YYYY-MM-DD format.
+}
+
+// Validate checks the parameters against Xero's rules and returns a *ParamError for the first invalid one.
+func (p AgedReportParams) Validate() error {
+	if p.ContactID == "" {
+		return &ParamError{
+			Param:  "contactID",
+			Value:  "",
+			Reason: "is required",
+		}
+	}
+
+	if err := validateID("contactID", p.ContactID); err != nil {
+		return err
+	}
+
+	if err := validateDate("date", p.Date); err != nil {
+		return err
+	}
+
+	return validateDateRange(p.FromDate, p.ToDate, 0)
+}
+
+// Query encodes the parameters into a query string, skipping zero values.
+func (p AgedReportParams) Query() url.Values {
+	query := url.Values{}
+
+	setString(query, "contactID", p.ContactID)
+	setString(query, "date", p.Date)
+	setString(query, "fromDate", p.FromDate)
+	setString(query, "toDate", p.ToDate)
+
+	return query
+}
+
+func setBool(query url.Values, name string, value bool) {
+	if value {
+		query.Set(name, "true")
+	}
+}
+
+func setInt(query url.Values, name string, value int) {
+	if value != 0 {
+		query.Set(name, strconv.Itoa(value))
+	}
+}
+
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+func validateDate(name, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return &ParamError{
+			Param:  name,
+			Value:  value,
+			Reason: "expected a date in YYYY-MM-DD format",
+		}
+	}
+
+	return nil
+}
+
+// validateDateRange checks that both dates are valid, and that they span no more than maxDays (zero means no limit).
+func validateDateRange(from, to string, maxDays int) error {
+	if err := validateDate("fromDate", from); err != nil {
+		return err
+	}
+
+	if err := validateDate("toDate", to); err != nil {
+		return err
+	}
+
+	if from == "" || to == "" {
+		return nil
+	}
+
+	fromDate, _ := time.Parse(time.DateOnly, from)
+	toDate, _ := time.Parse(time.DateOnly, to)
+
+	switch {
+	case toDate.Before(fromDate):
+		return &ParamError{
+			Param:  "toDate",
+			Value:  to,
+			Reason: "must not be before fromDate",
+		}
+	case maxDays > 0 && toDate.Sub(fromDate) > time.Duration(maxDays)*24*time.Hour:
+		return &ParamError{
+			Param:  "toDate",
+			Value:  to,
+			Reason: fmt.Sprintf("must be within %d days of fromDate", maxDays),
+		}
+	default:
+		return nil
+	}
+}
+
+func validateID(name, value string) error {
+	if value == "" || xeroUUIDFormat.MatchString(value) {
+		return nil
+	}
+
+	return &ParamError{
+		Param:  name,
+		Value:  value,
+		Reason: "expected a UUID",
+	}
+}
+
+func validatePeriods(value int) error {
+	if value == 0 || (value >= MinPeriods && value <= MaxPeriods) {
+		return nil
+	}
+
+	return &ParamError{
+		Param:  "periods",
+		Value:  strconv.Itoa(value),
+		Reason: fmt.Sprintf("must be between %d and %d", MinPeriods, MaxPeriods),
+	}
+}
+
+func validateTimeframe(value Timeframe) error {
+	if value == "" || value.Valid() {
+		return nil
+	}
+
+	return &ParamError{
+		Param:  "timeframe",
+		Value:  string(value),
+		Reason: "must be one of MONTH, QUARTER, YEAR",
+	}
+}
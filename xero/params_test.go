@@ -0,0 +1,205 @@
+package xero_test
+
+import (
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceSheetParamsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg   xero.BalanceSheetParams
+		param string
+	}{
+		"zero value": {
+			arg:   xero.BalanceSheetParams{},
+			param: "",
+		},
+		"all set": {
+			arg: xero.BalanceSheetParams{
+				Date:              "2024-08-25",
+				Periods:           11,
+				Timeframe:         xero.TimeframeQuarter,
+				TrackingOptionID1: "8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+				TrackingOptionID2: "297c2dc5-cc47-4afd-8ec8-74990b8761e9",
+				StandardLayout:    true,
+				PaymentsOnly:      true,
+			},
+			param: "",
+		},
+		"date - wrong format": {
+			arg:   xero.BalanceSheetParams{Date: "25/08/2024"},
+			param: "date",
+		},
+		"date - not a date": {
+			arg:   xero.BalanceSheetParams{Date: "2024-02-30"},
+			param: "date",
+		},
+		"periods - too low": {
+			arg:   xero.BalanceSheetParams{Periods: -1},
+			param: "periods",
+		},
+		"periods - too high": {
+			arg:   xero.BalanceSheetParams{Periods: 12},
+			param: "periods",
+		},
+		"timeframe - unknown": {
+			arg:   xero.BalanceSheetParams{Timeframe: "WEEK"},
+			param: "timeframe",
+		},
+		"timeframe - wrong case": {
+			arg:   xero.BalanceSheetParams{Timeframe: "month"},
+			param: "timeframe",
+		},
+		"trackingOptionID1 - not a UUID": {
+			arg:   xero.BalanceSheetParams{TrackingOptionID1: "abc"},
+			param: "trackingOptionID1",
+		},
+		"trackingOptionID2 - without trackingOptionID1": {
+			arg:   xero.BalanceSheetParams{TrackingOptionID2: "297c2dc5-cc47-4afd-8ec8-74990b8761e9"},
+			param: "trackingOptionID2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.arg.Validate()
+
+			if test.param == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var paramErr *xero.ParamError
+
+			assert.ErrorIs(t, err, xero.ErrInvalidParam)
+			assert.ErrorAs(t, err, &paramErr)
+			assert.Equal(t, test.param, paramErr.Param)
+		})
+	}
+}
+
+func TestBalanceSheetParamsQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg   xero.BalanceSheetParams
+		query string
+	}{
+		"zero value": {
+			arg:   xero.BalanceSheetParams{},
+			query: "",
+		},
+		"all set": {
+			arg: xero.BalanceSheetParams{
+				Date:              "2024-08-25",
+				Periods:           3,
+				Timeframe:         xero.TimeframeMonth,
+				TrackingOptionID1: "8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+				TrackingOptionID2: "297c2dc5-cc47-4afd-8ec8-74990b8761e9",
+				StandardLayout:    true,
+				PaymentsOnly:      true,
+			},
+			query: "date=2024-08-25&paymentsOnly=true&periods=3&standardLayout=true&timeframe=MONTH" +
+				"&trackingOptionID1=8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a&trackingOptionID2=297c2dc5-cc47-4afd-8ec8-74990b8761e9",
+		},
+		"false booleans are omitted": {
+			arg:   xero.BalanceSheetParams{Periods: 2, StandardLayout: false},
+			query: "periods=2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.query, test.arg.Query().Encode())
+		})
+	}
+}
+
+func TestProfitAndLossParamsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg   xero.ProfitAndLossParams
+		param string
+	}{
+		"zero value": {
+			arg:   xero.ProfitAndLossParams{},
+			param: "",
+		},
+		"all set": {
+			arg: xero.ProfitAndLossParams{
+				FromDate:            "2023-08-26",
+				ToDate:              "2024-08-25",
+				Periods:             3,
+				Timeframe:           xero.TimeframeMonth,
+				TrackingCategoryID:  "8a0f2e8b-3f0a-4a4e-9d76-4f8b1c0f5b1a",
+				TrackingOptionID:    "297c2dc5-cc47-4afd-8ec8-74990b8761e9",
+				TrackingCategoryID2: "70784a63-d24b-46a9-a4db-0e70a274b056",
+				TrackingOptionID2:   "e0da6937-de07-4a14-adee-37abfac298ce",
+				StandardLayout:      true,
+				PaymentsOnly:        true,
+			},
+			param: "",
+		},
+		"fromDate - wrong format": {
+			arg:   xero.ProfitAndLossParams{FromDate: "2024-8-1"},
+			param: "fromDate",
+		},
+		"toDate - before fromDate": {
+			arg:   xero.ProfitAndLossParams{FromDate: "2024-08-25", ToDate: "2024-08-24"},
+			param: "toDate",
+		},
+		"toDate - more than a year after fromDate": {
+			arg:   xero.ProfitAndLossParams{FromDate: "2023-08-24", ToDate: "2024-08-25"},
+			param: "toDate",
+		},
+		"periods - too high": {
+			arg:   xero.ProfitAndLossParams{Periods: 12},
+			param: "periods",
+		},
+		"timeframe - unknown": {
+			arg:   xero.ProfitAndLossParams{Timeframe: "DAY"},
+			param: "timeframe",
+		},
+		"trackingOptionID - without category": {
+			arg:   xero.ProfitAndLossParams{TrackingOptionID: "297c2dc5-cc47-4afd-8ec8-74990b8761e9"},
+			param: "trackingOptionID",
+		},
+		"trackingCategoryID2 - without first category": {
+			arg:   xero.ProfitAndLossParams{TrackingCategoryID2: "297c2dc5-cc47-4afd-8ec8-74990b8761e9"},
+			param: "trackingCategoryID2",
+		},
+		"trackingCategoryID - not a UUID": {
+			arg:   xero.ProfitAndLossParams{TrackingCategoryID: "region"},
+			param: "trackingCategoryID",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.arg.Validate()
+
+			if test.param == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var paramErr *xero.ParamError
+
+			assert.ErrorAs(t, err, &paramErr)
+			assert.Equal(t, test.param, paramErr.Param)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package xero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// call describes a single Xero API call, as handled by fetch.
+type call struct {
+	path     string     // Path relative to the client's base URL.
+	query    url.Values // Query string, may be nil.
+	tenantID string     // Value of the Xero-tenant-id header, omitted if empty.
+}
+
+// key identifies the call by tenant, path and query string.
+func (cl call) key() string {
+	return cl.tenantID + " " + cl.path + "?" + cl.query.Encode()
+}
+
+// checker is implemented by the payloads that carry a Status next to their data, such as reportEnvelope.
+type checker interface {
+	OK() bool
+}
+
+// fetch is the request pipeline shared by every endpoint: it sends the call, with retries and token refresh,
+// and decodes the response body into a T in a single pass.
+// If T implements checker, the payload must be OK or ErrBrokenResponse is returned.
+func fetch[T any](ctx context.Context, c *client, cl call) (*T, error) {
+	var v T
+
+	err := c.get(ctx, cl, func(body io.Reader) error {
+		return decode(body, &v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// decode reads the response body into v in a single pass, token by token if v implements streamDecoder.
+// It fails with ErrInvalidResponse if the body is not JSON, with ErrBrokenResponse if Xero reports an error in the
+// payload, or with ErrInvalidJSON if the payload does not match v.
+func decode(body io.Reader, v any) error {
+	var err error
+
+	dec := json.NewDecoder(body)
+
+	if sd, ok := v.(streamDecoder); ok {
+		err = sd.decodeStream(dec)
+	} else {
+		err = dec.Decode(v)
+	}
+
+	// Type errors do not stop the decoder, so the Status is known even if the payload does not match.
+	if err != nil && !isTypeError(err) {
+		if errors.Is(err, ErrBodyTooLarge) {
+			return err
+		}
+
+		return errors.Join(ErrInvalidResponse, err)
+	}
+
+	if chk, ok := v.(checker); ok && !chk.OK() {
+		return ErrBrokenResponse
+	}
+
+	if err != nil {
+		return errors.Join(ErrInvalidJSON, err)
+	}
+
+	return nil
+}
+
+// limitedReader reads up to max bytes, then fails with ErrBodyTooLarge if the body has more.
+type limitedReader struct {
+	max       int64
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.tooLarge()
+	}
+
+	// Read one byte past the limit to tell a body of exactly max bytes from a larger one.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+
+	if l.remaining < 0 {
+		return n - 1, l.tooLarge()
+	}
+
+	return n, err //nolint:wrapcheck // io.Reader errors, such as io.EOF, are returned as they are
+}
+
+// tooLarge returns the error of a body larger than the limit.
+func (l *limitedReader) tooLarge() error {
+	return errors.Join(ErrBodyTooLarge, errors.New("more than "+strconv.FormatInt(l.max, 10)+" bytes")) //nolint:err113 // Detail of ErrBodyTooLarge
+}
+
+// classify returns an *APIError for responses with a status other than 200.
+func classify(resp *http.Response, path string) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError(resp, path)
+}
+
+// get sends a GET request for the call and passes the response body to decode.
+// Requests failing with ErrTooManyRequests or ErrXeroDown are retried according to the client's RetryPolicy, unless
+// the client-side rate limiter gave up before sending them. Requests failing with 401 are retried once with a fresh access token.
+func (c *client) get(ctx context.Context, cl call, decode func(io.Reader) error) error {
+	refreshed := false
+
+	for attempt := 1; ; attempt++ {
+		token, err := c.send(ctx, cl, attempt, decode)
+
+		if errors.Is(err, ErrUnauthorized) && !refreshed && c.invalidate(token) {
+			c.logger.Warn("Xero rejected the access token, refreshing", "endpoint", cl.path, "attempt", attempt)
+
+			refreshed = true
+
+			continue
+		}
+
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		delay, ok := c.retry.delay(attempt, err)
+		if !ok {
+			c.logger.Warn("Xero asked to wait longer than the retry policy allows, giving up", "endpoint", cl.path, "attempt", attempt, "delay", delay)
+
+			return err
+		}
+
+		c.logger.Warn("Xero request failed, retrying", "endpoint", cl.path, "attempt", attempt, "delay", delay, "err", err)
+
+		if waitErr := wait(ctx, delay); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+}
+
+// invalidate discards a token rejected by Xero, and returns whether the request can be attempted again with a new one.
+func (c *client) invalidate(token *Token) bool {
+	invalidator, ok := c.tokens.(tokenInvalidator)
+
+	return ok && token != nil && invalidator.Invalidate(token)
+}
+
+// send makes a single GET request for the call and passes the response body to decode if the status is 200.
+// It returns the access token that was used. Bodies larger than the client's maximum size fail with ErrBodyTooLarge.
+func (c *client) send(ctx context.Context, cl call, attempt int, decode func(io.Reader) error) (*Token, error) {
+	var token *Token
+
+	query := cl.query.Encode()
+
+	endpoint := c.base + cl.path
+	if query != "" {
+		endpoint += "?" + query
+	}
+
+	c.logger.Debug("Outgoing HTTP request", "endpoint", cl.path, "query", query, "tenant", cl.tenantID, "attempt", attempt)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, errors.Join(ErrHTTPFailure, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if cl.tenantID != "" {
+		req.Header.Set("Xero-tenant-id", cl.tenantID)
+	}
+
+	// The slot may take a while to free up, the token is fetched afterwards so that it does not expire in the meantime.
+	release, err := c.limiter.acquire(ctx, cl.tenantID)
+	if err != nil {
+		c.metrics.observe(cl, err)
+
+		return nil, err
+	}
+
+	if c.tokens != nil {
+		if token, err = c.tokens.Token(ctx); err != nil {
+			release()
+			c.metrics.observe(cl, err)
+
+			return nil, err //nolint:wrapcheck // Token sources return ErrTokenFailure
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	}
+
+	err = c.exchange(req, cl, attempt, decode)
+
+	c.metrics.observe(cl, err)
+	release()
+
+	// Xero rejects tenants that are not connected, there is no budget to keep track of.
+	if errors.Is(err, ErrForbidden) {
+		c.limiter.forget(cl.tenantID)
+	}
+
+	return token, err
+}
+
+// exchange sends the request and passes the response body to decode if the status is 200.
+func (c *client) exchange(req *http.Request, cl call, attempt int, decode func(io.Reader) error) error {
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return errors.Join(ErrRequestFailure, err)
+	}
+
+	c.limiter.update(cl.tenantID, resp.Header)
+
+	c.logger.Debug("HTTP request finished", "status", resp.StatusCode, "attempt", attempt)
+
+	defer resp.Body.Close()
+
+	if err = classify(resp, cl.path); err != nil {
+		return err
+	}
+
+	if c.maxBodySize > 0 && resp.ContentLength > c.maxBodySize {
+		return errors.Join(ErrBodyTooLarge, errors.New("Content-Length is "+strconv.FormatInt(resp.ContentLength, 10)+" bytes")) //nolint:err113 // Detail of ErrBodyTooLarge
+	}
+
+	var body io.Reader = resp.Body
+
+	if c.maxBodySize > 0 {
+		body = &limitedReader{max: c.maxBodySize, r: resp.Body, remaining: c.maxBodySize}
+	}
+
+	return decode(body)
+}
@@ -0,0 +1,334 @@
+package xero
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// errRateLimited is joined to the ErrTooManyRequests of calls the rate limiter gave up on, they are not retried as no
+// request was sent to Xero.
+var errRateLimited = errors.New("rate limiter gave up waiting for a slot")
+
+// RateLimits configures the client-side rate limiter, limits apply to each tenant unless stated otherwise.
+// Zero values disable the corresponding limit.
+// See https://developer.xero.com/documentation/guides/oauth2/limits/#api-rate-limits
+type RateLimits struct {
+	Concurrent   int // Maximum number of calls in progress at the same time.
+	PerDay       int // Maximum number of calls in a rolling 24-hour window.
+	PerMinute    int // Maximum number of calls in a rolling 60-second window.
+	AppPerMinute int // Maximum number of calls in a rolling 60-second window across the whole app.
+
+	MaxWait time.Duration // Calls that would wait longer for a slot fail with ErrTooManyRequests right away.
+}
+
+// DefaultRateLimits returns Xero's documented limits.
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Concurrent:   5,                //nolint:mnd // Xero's concurrent limit
+		PerDay:       5000,             //nolint:mnd // Xero's daily limit
+		PerMinute:    60,               //nolint:mnd // Xero's minute limit
+		AppPerMinute: 10000,            //nolint:mnd // Xero's app minute limit
+		MaxWait:      30 * time.Second, //nolint:mnd // Sensible default for interactive requests
+	}
+}
+
+// RateBudget contains the number of calls that can still be made before hitting a limit.
+// Values are tracked locally and lowered whenever Xero reports a smaller budget. A negative value means unlimited.
+type RateBudget struct {
+	Concurrent int `json:"concurrent"`
+	Day        int `json:"day"`
+	Minute     int `json:"minute"`
+	AppMinute  int `json:"appMinute"`
+}
+
+// maxTenants is the number of tenants tracked by a rate limiter before the least recently used one is evicted.
+const maxTenants = 256
+
+// rateLimiter blocks calls that would exceed the configured limits.
+// Limits are tracked per tenant, except AppPerMinute which is shared by all tenants. Tenants are tracked from their
+// first call, until Xero rejects them or, past maxTenants, until they are the least recently used.
+type rateLimiter struct {
+	app     *window
+	changed chan struct{} // Closed and replaced every time a call is released.
+	clock   uint64        // Incremented every time a tenant is used.
+	limits  RateLimits
+	mu      sync.Mutex
+	tenants map[string]*tenantBudget
+}
+
+// tenantBudget tracks the calls made for a single tenant.
+type tenantBudget struct {
+	day      *window
+	inflight int
+	minute   *window
+	used     uint64 // Value of the limiter's clock when the tenant was last used.
+}
+
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	return &rateLimiter{
+		app:     &window{calls: nil, limit: limits.AppPerMinute, period: time.Minute},
+		changed: make(chan struct{}),
+		clock:   0,
+		limits:  limits,
+		mu:      sync.Mutex{},
+		tenants: make(map[string]*tenantBudget),
+	}
+}
+
+// acquire blocks until a call can be made for the tenant, then returns a function that must be called once the call is done.
+// It fails with ErrTooManyRequests if the context is done, or would be done, before a slot frees up, or if the wait
+// exceeds the limits' MaxWait, such as when the daily limit is used up.
+func (rl *rateLimiter) acquire(ctx context.Context, tenantID string) (func(), error) {
+	for {
+		rl.mu.Lock()
+
+		now := time.Now()
+		tb := rl.tenant(tenantID)
+		delay := max(rl.app.delay(now), tb.day.delay(now), tb.minute.delay(now))
+
+		if delay == 0 && (rl.limits.Concurrent <= 0 || tb.inflight < rl.limits.Concurrent) {
+			tb.inflight++
+			rl.app.add(now)
+			tb.day.add(now)
+			tb.minute.add(now)
+			rl.mu.Unlock()
+
+			return func() { rl.release(tb) }, nil
+		}
+
+		changed := rl.changed
+
+		rl.mu.Unlock()
+
+		if rl.limits.MaxWait > 0 && delay > rl.limits.MaxWait {
+			return nil, errors.Join(ErrTooManyRequests, errRateLimited, errors.New("next slot in "+delay.Round(time.Second).String())) //nolint:err113 // Detail of ErrTooManyRequests
+		}
+
+		if err := rl.wait(ctx, delay, changed); err != nil {
+			return nil, errors.Join(ErrTooManyRequests, errRateLimited, err)
+		}
+	}
+}
+
+// budget returns the remaining budget of the tenant.
+func (rl *rateLimiter) budget(tenantID string) RateBudget {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	tb, ok := rl.tenants[tenantID]
+	if !ok {
+		tb = rl.newTenantBudget() // Not tracked, the whole budget is left.
+	}
+
+	return rl.remaining(tb, time.Now())
+}
+
+// budgets returns the remaining budget of every tenant a call was made for.
+func (rl *rateLimiter) budgets() map[string]RateBudget {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	budgets := make(map[string]RateBudget, len(rl.tenants))
+
+	for tenantID, tb := range rl.tenants {
+		budgets[tenantID] = rl.remaining(tb, now)
+	}
+
+	return budgets
+}
+
+// appRemaining returns the calls that can still be made in the current minute by the whole app, -1 if unlimited.
+func (rl *rateLimiter) appRemaining() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.app.remaining(time.Now())
+}
+
+// remaining returns the remaining budget of the tenant. It must be called with the lock held.
+func (rl *rateLimiter) remaining(tb *tenantBudget, now time.Time) RateBudget {
+	concurrent := -1
+
+	if rl.limits.Concurrent > 0 {
+		concurrent = rl.limits.Concurrent - tb.inflight
+	}
+
+	return RateBudget{
+		Concurrent: concurrent,
+		Day:        tb.day.remaining(now),
+		Minute:     tb.minute.remaining(now),
+		AppMinute:  rl.app.remaining(now),
+	}
+}
+
+// release marks a call as finished and wakes up any waiting call.
+func (rl *rateLimiter) release(tb *tenantBudget) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	tb.inflight--
+
+	close(rl.changed)
+	rl.changed = make(chan struct{})
+}
+
+// forget stops tracking the tenant, unless a call is still in progress for it.
+func (rl *rateLimiter) forget(tenantID string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if tb, ok := rl.tenants[tenantID]; ok && tb.inflight == 0 {
+		delete(rl.tenants, tenantID)
+	}
+}
+
+// newTenantBudget returns the budget of a tenant no call was made for.
+func (rl *rateLimiter) newTenantBudget() *tenantBudget {
+	return &tenantBudget{
+		day:      &window{calls: nil, limit: rl.limits.PerDay, period: 24 * time.Hour}, //nolint:mnd // One day
+		inflight: 0,
+		minute:   &window{calls: nil, limit: rl.limits.PerMinute, period: time.Minute},
+		used:     0,
+	}
+}
+
+// tenant returns the budget of the tenant, creating it if needed, and marks it as used.
+// It must be called with the lock held. The least recently used tenant is evicted once maxTenants are tracked.
+func (rl *rateLimiter) tenant(tenantID string) *tenantBudget {
+	rl.clock++
+
+	tb, ok := rl.tenants[tenantID]
+	if !ok {
+		if len(rl.tenants) >= maxTenants {
+			rl.evict()
+		}
+
+		tb = rl.newTenantBudget()
+		rl.tenants[tenantID] = tb
+	}
+
+	tb.used = rl.clock
+
+	return tb
+}
+
+// evict stops tracking the least recently used tenant. It must be called with the lock held.
+// Calls in progress for it are released as usual, its budget is tracked anew on its next call.
+func (rl *rateLimiter) evict() {
+	var (
+		lru    string
+		oldest *tenantBudget
+	)
+
+	for tenantID, tb := range rl.tenants {
+		if oldest == nil || tb.used < oldest.used {
+			lru, oldest = tenantID, tb
+		}
+	}
+
+	delete(rl.tenants, lru)
+}
+
+// update lowers the local budget of the tenant to the one reported by Xero in the response headers.
+func (rl *rateLimiter) update(tenantID string, header http.Header) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	tb := rl.tenant(tenantID)
+
+	rl.app.sync(now, header.Get("X-AppMinLimit-Remaining"))
+	tb.day.sync(now, header.Get("X-DayLimit-Remaining"))
+	tb.minute.sync(now, header.Get("X-MinLimit-Remaining"))
+}
+
+// wait blocks until either the delay expires or a call is released.
+func (rl *rateLimiter) wait(ctx context.Context, delay time.Duration, changed <-chan struct{}) error {
+	if delay == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // Context errors are returned as they are
+		case <-changed:
+			return nil
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // Context errors are returned as they are
+	case <-changed:
+		return nil
+	case <-timer.C:
+		return nil
+	}
+}
+
+// window is a rolling window that keeps track of the calls made in the last period.
+type window struct {
+	calls  []time.Time
+	limit  int
+	period time.Duration
+}
+
+// add records a call.
+func (w *window) add(now time.Time) {
+	if w.limit > 0 {
+		w.calls = append(w.calls, now)
+	}
+}
+
+// delay returns how long to wait before the window allows another call.
+func (w *window) delay(now time.Time) time.Duration {
+	if w.remaining(now) != 0 {
+		return 0
+	}
+
+	return w.calls[len(w.calls)-w.limit].Add(w.period).Sub(now)
+}
+
+// prune forgets the calls that fell out of the window.
+func (w *window) prune(now time.Time) {
+	cutoff := now.Add(-w.period)
+
+	i := 0
+	for i < len(w.calls) && !w.calls[i].After(cutoff) {
+		i++
+	}
+
+	w.calls = w.calls[i:]
+}
+
+// remaining returns how many calls can still be made, or -1 if the window has no limit.
+func (w *window) remaining(now time.Time) int {
+	if w.limit <= 0 {
+		return -1
+	}
+
+	w.prune(now)
+
+	return max(w.limit-len(w.calls), 0)
+}
+
+// sync records phantom calls until the local budget is not greater than the one reported by Xero.
+func (w *window) sync(now time.Time, value string) {
+	reported, err := strconv.Atoi(value)
+	if err != nil || w.limit <= 0 {
+		return
+	}
+
+	for range w.remaining(now) - max(reported, 0) {
+		w.calls = append(w.calls, now)
+	}
+}
@@ -0,0 +1,277 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/metrics"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingHTTPDoer waits for the release channel to be closed, or the request to be cancelled, before replying.
+// It tracks the number of calls and the peak of concurrent ones.
+type blockingHTTPDoer struct {
+	body    []byte
+	calls   atomic.Int32
+	current atomic.Int32
+	peak    atomic.Int32
+	release chan struct{}
+}
+
+func (m *blockingHTTPDoer) Do(req *http.Request) (*http.Response, error) {
+	m.calls.Add(1)
+
+	current := m.current.Add(1)
+	defer m.current.Add(-1)
+
+	for {
+		peak := m.peak.Load()
+		if current <= peak || m.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+
+	select {
+	case <-m.release:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader(m.body)),
+		Header:     http.Header{},
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func TestRateLimitConcurrent(t *testing.T) {
+	t.Parallel()
+
+	doer := &blockingHTTPDoer{
+		body:    fixture(t, "testdata/reports.json"),
+		release: make(chan struct{}),
+	}
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithRateLimits(xero.RateLimits{Concurrent: 2})
+
+	var wg sync.WaitGroup
+
+	// Parameters differ, so that the calls are not coalesced.
+	for periods := range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{Periods: periods + 1})
+			assert.NoError(t, err)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return doer.current.Load() == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 0, client.RateBudget("").Concurrent)
+
+	close(doer.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), doer.peak.Load())
+	assert.Equal(t, 2, client.RateBudget("").Concurrent)
+}
+
+func TestRateLimitPerMinute(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingHTTPDoer{
+		body:   fixture(t, "testdata/reports.json"),
+		status: http.StatusOK,
+	}
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithRateLimits(xero.RateLimits{PerMinute: 2, PerDay: 10})
+
+	for range 2 {
+		_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, xero.RateBudget{Concurrent: -1, Day: 8, Minute: 0, AppMinute: -1}, client.RateBudget(""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.BalanceSheet(ctx, xero.BalanceSheetParams{})
+
+	assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, doer.requests, 2)
+}
+
+func TestRateLimitMaxWait(t *testing.T) {
+	t.Parallel()
+
+	doer := &sequenceHTTPDoer{
+		responses: []mockResponse{
+			{
+				body:   fixture(t, "testdata/reports.json"),
+				header: http.Header{"X-Daylimit-Remaining": []string{"0"}},
+				status: http.StatusOK,
+			},
+		},
+	}
+
+	var logs bytes.Buffer
+
+	tokens := &countingSource{}
+
+	client := xero.HTTPClient(slog.New(slog.NewTextHandler(&logs, nil))).
+		WithHTTPClient(doer).
+		WithRateLimits(xero.RateLimits{PerDay: 5000, MaxWait: time.Second}).
+		WithRetryPolicy(xero.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}).
+		WithTokenSource(tokens)
+
+	_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	// The daily limit is used up, the call fails right away even though the context has no deadline.
+	start := time.Now()
+
+	_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{Periods: 2})
+
+	assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, doer.Calls())
+
+	// Neither retried, nor given a token it would not use.
+	assert.NotContains(t, logs.String(), "retrying")
+	assert.Equal(t, int32(1), tokens.calls.Load())
+}
+
+// countingSource returns a static token and counts the calls.
+type countingSource struct {
+	calls atomic.Int32
+}
+
+func (s *countingSource) Token(context.Context) (*xero.Token, error) {
+	s.calls.Add(1)
+
+	return &xero.Token{AccessToken: "my-token"}, nil
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	t.Parallel()
+
+	doer := &sequenceHTTPDoer{
+		responses: []mockResponse{
+			{
+				body: fixture(t, "testdata/reports.json"),
+				header: http.Header{
+					"X-Appminlimit-Remaining": []string{"9000"},
+					"X-Daylimit-Remaining":    []string{"100"},
+					"X-Minlimit-Remaining":    []string{"0"},
+				},
+				status: http.StatusOK,
+			},
+		},
+	}
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(doer)
+
+	assert.Equal(t, xero.RateBudget{Concurrent: 5, Day: 5000, Minute: 60, AppMinute: 10000}, client.RateBudget(""))
+
+	_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, xero.RateBudget{Concurrent: 5, Day: 100, Minute: 0, AppMinute: 9000}, client.RateBudget(""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = client.BalanceSheet(ctx, xero.BalanceSheetParams{})
+
+	assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+	assert.Equal(t, 1, doer.Calls())
+}
+
+func TestRateLimitForbiddenTenant(t *testing.T) {
+	t.Parallel()
+
+	const (
+		connected = "70784a63-d24b-46a9-a4db-0e70a274b056"
+		unknown   = "9b2f1c7e-5d4a-4e3b-8f6a-2c1d0e9b8a70"
+	)
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(xero.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.Header.Get("Xero-tenant-id") == unknown {
+				status = http.StatusForbidden
+			}
+
+			return &http.Response{
+				Body:       io.NopCloser(bytes.NewReader(fixture(t, "testdata/reports.json"))),
+				Header:     http.Header{},
+				StatusCode: status,
+			}, nil
+		}))
+
+	_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant(connected))
+	assert.NoError(t, err)
+
+	_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant(unknown))
+	assert.ErrorIs(t, err, xero.ErrForbidden)
+
+	// Only the connected tenant is tracked.
+	assert.Equal(t, 4999, client.RateBudget(connected).Day)
+	assert.Equal(t, 5000, client.RateBudget(unknown).Day)
+}
+
+func TestRateLimitMaxTenants(t *testing.T) {
+	t.Parallel()
+
+	const tenants = 300 // More than the limiter tracks.
+
+	reg := metrics.NewRegistry()
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(fixtureHTTPDoer{body: fixture(t, "testdata/reports.json")}).
+		WithMetrics(reg).
+		WithRateLimits(xero.RateLimits{PerDay: 5000})
+
+	tenantID := func(i int) string {
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", i)
+	}
+
+	for i := range tenants {
+		_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant(tenantID(i)))
+		assert.NoError(t, err)
+
+		// The first tenant keeps being used.
+		_, err = client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant(tenantID(0)))
+		assert.NoError(t, err)
+	}
+
+	var out bytes.Buffer
+
+	assert.NoError(t, reg.Write(&out))
+	assert.Equal(t, 256, strings.Count(out.String(), `limit="day"`))
+
+	// The least recently used tenants are evicted, even though their calls are still in the day window.
+	assert.Equal(t, 5000, client.RateBudget(tenantID(1)).Day)
+	assert.Equal(t, 4999, client.RateBudget(tenantID(tenants-1)).Day)
+	assert.Equal(t, 5000-tenants-1, client.RateBudget(tenantID(0)).Day)
+}
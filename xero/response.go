@@ -2,25 +2,43 @@ package xero
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
 var (
+	ErrForbidden = errors.New("xero denied access to the resource") // Error returned for 403 status code, e.g. missing scopes or tenant not connected.
+
 	ErrInvalidRequest = errors.New("invalid parameter") // Error returned for 400 status code.
 
 	ErrInvalidTimestamp = errors.New("could not unmarshal .NET timestamp") // Error returned for non-integer UNIX timestamps.
 
+	ErrNotFound = errors.New("resource not found in Xero") // Error returned for 404 status code.
+
 	ErrTooManyRequests = errors.New("request hit the rate limit") // See https://developer.xero.com/documentation/guides/oauth2/limits/#api-rate-limits
 
+	ErrUnauthorized = errors.New("xero rejected the access token") // Error returned for 401 status code.
+
 	ErrXeroDown = errors.New("xero API is not reachable") // Error returned for any 5xx status code.
 
-	ErrZeroTimestamp = errors.New("invalid zero timestamp") // Error returned for zero and negative UNIX timestamps.
+	ErrZeroTimestamp = errors.New("invalid zero timestamp") // Error returned for zero UNIX timestamps.
 
 	XeroDateFormat = regexp.MustCompile(`Date\((?P<Value>\d+)\)`) // XeroDateFormat matches .NET JSON date format in a string.
 )
 
+// dotNetDate matches a whole .NET JSON date, with its sign and optional offset, e.g. /Date(1724536800000+1000)/.
+var dotNetDate = regexp.MustCompile(`^/Date\((?P<Value>-?\d+)(?P<Offset>[+-]\d{4})?\)/$`)
+
+// isoDateFormats are the ISO 8601 layouts found in Xero responses, dates without offset are in UTC.
+var isoDateFormats = []string{ //nolint:gochecknoglobals // Read-only list of layouts
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
 // Response contains fields common to all Xero API's responses.
 type Response struct {
 	Status string `description:"Actual HTTP response status" json:"status"`
@@ -31,34 +49,85 @@ func (r Response) OK() bool {
 	return r.Status == "OK"
 }
 
-// DateTimeField is a type that implements json.Unmarshaler for handling Microsoft .NET JSON date format as utilised by Xero API.
+// DateTimeField is a type that implements json.Marshaler and json.Unmarshaler for handling Microsoft .NET JSON date format
+// as utilised by Xero API. It also reads the ISO 8601 dates Xero returns in some fields, with or without time and offset.
 // See https://developer.xero.com/documentation/api/accounting/requests-and-responses#json-responses-and-date-formats
 type DateTimeField struct {
 	time.Time
 }
 
+// DotNet returns the date in .NET JSON format, e.g. /Date(1724536800000+1000)/. The offset is omitted for UTC dates.
+func (dt DateTimeField) DotNet() string {
+	_, offset := dt.Zone()
+	date := "/Date(" + strconv.FormatInt(dt.UnixMilli(), 10)
+
+	if offset != 0 {
+		sign := "+"
+		if offset < 0 {
+			sign = "-"
+			offset = -offset
+		}
+
+		date += sign + fmt.Sprintf("%02d%02d", offset/3600, offset%3600/60) //nolint:mnd // Seconds in an hour and a minute
+	}
+
+	return date + ")/"
+}
+
+// MarshalJSON satisfies json.Marshaler interface, dates are written in RFC 3339 format with their offset.
+func (dt DateTimeField) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + dt.Format(time.RFC3339Nano) + `"`), nil
+}
+
 // UnmarshalJSON satisfies json.Unmarshaler interface.
+// It accepts .NET dates, with or without offset, and ISO 8601 dates. Empty and null values leave the date unchanged.
 func (dt *DateTimeField) UnmarshalJSON(data []byte) error {
-	xeroDate := string(data)
-	if len(xeroDate) == 0 {
+	value := strings.ReplaceAll(strings.Trim(string(data), `"`), `\/`, "/")
+	if value == "" || value == "null" {
 		return nil
 	}
 
-	matches := XeroDateFormat.FindStringSubmatch(xeroDate)
-	if len(matches) != 2 { //nolint:mnd // Expects exactly two matches.
-		return nil
+	if matches := dotNetDate.FindStringSubmatch(value); matches != nil {
+		return dt.parseDotNet(matches)
 	}
 
-	unixTS, err := strconv.ParseInt(matches[1], 10, 64)
+	for _, layout := range isoDateFormats {
+		if date, err := time.Parse(layout, value); err == nil {
+			*dt = DateTimeField{Time: date}
+
+			return nil
+		}
+	}
+
+	return errors.Join(ErrInvalidTimestamp, errors.New(value)) //nolint:err113 // Detail of ErrInvalidTimestamp
+}
+
+// parseDotNet reads the milliseconds and offset matched by dotNetDate, dates before 1970 are negative.
+func (dt *DateTimeField) parseDotNet(matches []string) error {
+	millis, err := strconv.ParseInt(matches[1], 10, 64)
 
 	switch {
 	case err != nil:
 		return errors.Join(ErrInvalidTimestamp, err)
-	case unixTS <= 0:
+	case millis == 0:
 		return ErrZeroTimestamp
-	default:
-		*dt = DateTimeField{time.Unix(unixTS/1000, 0)} //nolint:mnd  // 1000 is one millisecond
+	}
 
-		return nil
+	date := time.UnixMilli(millis).UTC()
+
+	if offset := matches[2]; offset != "" {
+		hours, _ := strconv.Atoi(offset[1:3])
+		minutes, _ := strconv.Atoi(offset[3:5])
+		seconds := hours*3600 + minutes*60 //nolint:mnd // Seconds in an hour and a minute
+
+		if offset[0] == '-' {
+			seconds = -seconds
+		}
+
+		date = date.In(time.FixedZone("", seconds))
 	}
+
+	*dt = DateTimeField{Time: date}
+
+	return nil
 }
@@ -2,7 +2,6 @@ package xero_test
 
 import (
 	"encoding/json"
-	"errors"
 	"testing"
 	"time"
 
@@ -13,37 +12,80 @@ import (
 func TestUnmarshalJSON(t *testing.T) {
 	t.Parallel()
 
-	timePtr := func(timeStr string) *time.Time {
-		ptr, err := time.Parse(time.RFC3339, timeStr)
-		assert.NoError(t, err)
-
-		return &ptr
-	}
-
 	tests := map[string]struct {
 		arg    string
 		err    error
-		result *time.Time
+		result string // RFC 3339, empty for the zero time.
 	}{
 		"empty string": {
 			arg:    "",
 			err:    nil,
-			result: nil,
+			result: "",
 		},
-		"non-date string": {
-			arg:    "not a date",
+		"null": {
+			arg:    "null",
 			err:    nil,
-			result: nil,
+			result: "",
+		},
+		"non-date string": {
+			arg:    `"not a date"`,
+			err:    xero.ErrInvalidTimestamp,
+			result: "",
 		},
 		".NET datetime": {
 			arg:    `\/Date(1724536800000)\/`,
 			err:    nil,
-			result: timePtr("2024-08-24T22:00:00+00:00"),
+			result: "2024-08-24T22:00:00Z",
+		},
+		".NET datetime - JSON string": {
+			arg:    `"\/Date(1724595191626)\/"`,
+			err:    nil,
+			result: "2024-08-25T14:13:11.626Z",
+		},
+		".NET datetime - positive offset": {
+			arg:    `"/Date(1724536800000+1000)/"`,
+			err:    nil,
+			result: "2024-08-25T08:00:00+10:00",
+		},
+		".NET datetime - negative offset": {
+			arg:    `"/Date(1724536800000-0530)/"`,
+			err:    nil,
+			result: "2024-08-24T16:30:00-05:30",
+		},
+		".NET datetime - before 1970": {
+			arg:    `"/Date(-86400000)/"`,
+			err:    nil,
+			result: "1969-12-31T00:00:00Z",
 		},
 		"Zero datetime": {
 			arg:    `\/Date(0)\/`,
-			err:    errors.New("invalid zero timestamp"),
-			result: nil,
+			err:    xero.ErrZeroTimestamp,
+			result: "",
+		},
+		".NET datetime - malformed offset": {
+			arg:    `"/Date(1724536800000+10)/"`,
+			err:    xero.ErrInvalidTimestamp,
+			result: "",
+		},
+		".NET datetime - overflow": {
+			arg:    `"/Date(99999999999999999999)/"`,
+			err:    xero.ErrInvalidTimestamp,
+			result: "",
+		},
+		"ISO date": {
+			arg:    `"2024-08-25"`,
+			err:    nil,
+			result: "2024-08-25T00:00:00Z",
+		},
+		"ISO datetime without offset": {
+			arg:    `"2019-07-09T23:40:30.1833130"`,
+			err:    nil,
+			result: "2019-07-09T23:40:30.183313Z",
+		},
+		"RFC 3339": {
+			arg:    `"2024-08-25T08:00:00+10:00"`,
+			err:    nil,
+			result: "2024-08-25T08:00:00+10:00",
 		},
 	}
 
@@ -56,15 +98,67 @@ func TestUnmarshalJSON(t *testing.T) {
 			err := dt.UnmarshalJSON([]byte(test.arg))
 
 			if test.err != nil {
-				assert.EqualError(t, test.err, err.Error())
+				assert.ErrorIs(t, err, test.err)
 				assert.Empty(t, dt)
-			} else {
-				assert.NoError(t, err)
+
+				return
 			}
 
-			if test.result != nil {
-				assert.EqualValues(t, test.result.UTC(), dt.UTC())
+			assert.NoError(t, err)
+
+			if test.result == "" {
+				assert.True(t, dt.IsZero())
+
+				return
 			}
+
+			assert.Equal(t, test.result, dt.Format(time.RFC3339Nano))
+		})
+	}
+}
+
+func TestDateTimeFieldRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dotNet  string
+		rfc3339 string
+	}{
+		"UTC": {
+			dotNet:  "/Date(1724595191626)/",
+			rfc3339: `"2024-08-25T14:13:11.626Z"`,
+		},
+		"offset": {
+			dotNet:  "/Date(1724536800000+1000)/",
+			rfc3339: `"2024-08-25T08:00:00+10:00"`,
+		},
+		"negative offset": {
+			dotNet:  "/Date(1724536800000-0530)/",
+			rfc3339: `"2024-08-24T16:30:00-05:30"`,
+		},
+		"before 1970": {
+			dotNet:  "/Date(-2208988800000)/",
+			rfc3339: `"1900-01-01T00:00:00Z"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var fromDotNet, fromRFC3339 xero.DateTimeField
+
+			assert.NoError(t, json.Unmarshal([]byte(`"`+test.dotNet+`"`), &fromDotNet))
+			assert.Equal(t, test.dotNet, fromDotNet.DotNet())
+
+			data, err := json.Marshal(fromDotNet)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.rfc3339, string(data))
+
+			assert.NoError(t, json.Unmarshal(data, &fromRFC3339))
+			assert.Equal(t, test.dotNet, fromRFC3339.DotNet())
+			assert.True(t, fromDotNet.Equal(fromRFC3339.Time))
 		})
 	}
 }
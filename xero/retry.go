@@ -0,0 +1,95 @@
+package xero
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of requests failing with ErrTooManyRequests or ErrXeroDown.
+// Delays are computed with exponential backoff and full jitter, see https://encore.dev/blog/retries.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts, including the first one. Values lower than 2 disable retries.
+	BaseDelay   time.Duration // Upper bound of the first delay, doubled on every following attempt.
+	MaxDelay    time.Duration // Upper bound of any delay. Requests are not retried if Xero asks to wait longer with a Retry-After header.
+}
+
+// DefaultRetryPolicy returns a retry policy suitable for interactive requests.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,                      //nolint:mnd // Three retries
+		BaseDelay:   250 * time.Millisecond, //nolint:mnd // Sensible default
+		MaxDelay:    5 * time.Second,        //nolint:mnd // Sensible default
+	}
+}
+
+// delay returns how long to wait before the attempt following the given one, and false if the request must not be
+// retried. A Retry-After value sent by Xero takes precedence over the computed backoff, unless it exceeds MaxDelay, as
+// with the daily limit, in which case the request is not retried.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	var apiErr *APIError
+
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, apiErr.RetryAfter <= p.MaxDelay
+	}
+
+	ceiling := p.MaxDelay
+
+	if shift := attempt - 1; shift < 63 { //nolint:mnd // Avoid overflowing int64
+		if backoff := p.BaseDelay << shift; backoff > 0 && backoff < ceiling {
+			ceiling = backoff
+		}
+	}
+
+	if ceiling <= 0 {
+		return 0, true
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1)), true //nolint:gosec // Jitter does not need a secure source
+}
+
+// retryable returns whether a failed request should be attempted again.
+// Calls the client-side rate limiter gave up on are not, waiting again would not free up a slot any sooner.
+func retryable(err error) bool {
+	return (errors.Is(err, ErrTooManyRequests) && !errors.Is(err, errRateLimited)) || errors.Is(err, ErrXeroDown)
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+// It returns zero when the header is missing or invalid.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
+
+// wait blocks for the given duration or until the context is done.
+// It returns immediately if the context's deadline would expire before the delay.
+func wait(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // Context errors are returned as they are
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,262 @@
+package xero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/cassette"
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequenceHTTPDoer replies with the given responses in order, repeating the last one once they are exhausted.
+type sequenceHTTPDoer struct {
+	calls     int
+	mu        sync.Mutex
+	responses []mockResponse
+}
+
+type mockResponse struct {
+	body   []byte
+	header http.Header
+	status int
+}
+
+func (m *sequenceHTTPDoer) Do(_ *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := m.responses[min(m.calls, len(m.responses)-1)]
+	m.calls++
+
+	header := res.header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewReader(res.body)),
+		Header:     header,
+		StatusCode: res.status,
+	}, nil
+}
+
+func (m *sequenceHTTPDoer) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.calls
+}
+
+func TestBalanceSheetRetries(t *testing.T) {
+	t.Parallel()
+
+	policy := xero.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+
+	tests := map[string]struct {
+		policy    xero.RetryPolicy
+		responses []int
+		calls     int
+		err       error
+	}{
+		"no retries by default": {
+			policy:    xero.RetryPolicy{},
+			responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			calls:     1,
+			err:       xero.ErrXeroDown,
+		},
+		"success after 5xx": {
+			policy:    policy,
+			responses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			calls:     3,
+			err:       nil,
+		},
+		"success after 429": {
+			policy:    policy,
+			responses: []int{http.StatusTooManyRequests, http.StatusOK},
+			calls:     2,
+			err:       nil,
+		},
+		"max attempts reached": {
+			policy:    policy,
+			responses: []int{http.StatusInternalServerError},
+			calls:     3,
+			err:       xero.ErrXeroDown,
+		},
+		"400 is not retried": {
+			policy:    policy,
+			responses: []int{http.StatusBadRequest, http.StatusOK},
+			calls:     1,
+			err:       xero.ErrInvalidRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &sequenceHTTPDoer{}
+			for _, status := range test.responses {
+				doer.responses = append(doer.responses, mockResponse{
+					body:   fixture(t, "testdata/reports.json"),
+					status: status,
+				})
+			}
+
+			resp, err := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				WithRetryPolicy(test.policy).
+				BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+			assert.Equal(t, test.calls, doer.Calls())
+
+			if test.err != nil {
+				assert.Nil(t, resp)
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, resp.Reports, 1)
+		})
+	}
+}
+
+func TestBalanceSheetRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	doer := &sequenceHTTPDoer{
+		responses: []mockResponse{
+			{
+				header: http.Header{"Retry-After": []string{"1"}},
+				status: http.StatusTooManyRequests,
+			},
+			{
+				body:   fixture(t, "testdata/reports.json"),
+				status: http.StatusOK,
+			},
+		},
+	}
+
+	start := time.Now()
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithRetryPolicy(xero.RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    2 * time.Second,
+		}).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, doer.Calls())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestBalanceSheetRetryAfterTooLong(t *testing.T) {
+	t.Parallel()
+
+	// E.g. the daily limit was hit.
+	doer := &sequenceHTTPDoer{
+		responses: []mockResponse{
+			{
+				header: http.Header{"Retry-After": []string{"7200"}},
+				status: http.StatusTooManyRequests,
+			},
+		},
+	}
+
+	start := time.Now()
+
+	_, err := xero.HTTPClient(nil).
+		WithHTTPClient(doer).
+		WithRetryPolicy(xero.DefaultRetryPolicy()).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{})
+
+	assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+	assert.Equal(t, 1, doer.Calls())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestBalanceSheetRetryCancelled(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ctx func() (context.Context, context.CancelFunc)
+		err error
+	}{
+		"cancelled while waiting": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				time.AfterFunc(50*time.Millisecond, cancel)
+
+				return ctx, cancel
+			},
+			err: context.Canceled,
+		},
+		"deadline shorter than Retry-After": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			err: context.DeadlineExceeded,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &sequenceHTTPDoer{
+				responses: []mockResponse{
+					{
+						header: http.Header{"Retry-After": []string{"3"}},
+						status: http.StatusTooManyRequests,
+					},
+				},
+			}
+
+			ctx, cancel := test.ctx()
+			defer cancel()
+
+			start := time.Now()
+
+			_, err := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				WithRetryPolicy(xero.DefaultRetryPolicy()).
+				BalanceSheet(ctx, xero.BalanceSheetParams{})
+
+			assert.ErrorIs(t, err, xero.ErrTooManyRequests)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, 1, doer.Calls())
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+// TestBalanceSheetRetryReplay replays a 503 followed by a 200, as recorded from Xero.
+func TestBalanceSheetRetryReplay(t *testing.T) {
+	t.Parallel()
+
+	replayer, err := cassette.Replayer(bytes.NewReader(fixture(t, "testdata/retry.jsonl")))
+	assert.NoError(t, err)
+
+	rr, err := xero.HTTPClient(nil).
+		WithHTTPClient(replayer).
+		WithRetryPolicy(xero.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}).
+		BalanceSheet(context.TODO(), xero.BalanceSheetParams{Date: "2024-08-25"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Sheet", rr.Reports[0].ReportName)
+	assert.Empty(t, replayer.Unused())
+}
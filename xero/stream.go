@@ -0,0 +1,200 @@
+package xero
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// streamDecoder is implemented by payloads that decode themselves token by token, so that large bodies are never
+// buffered whole: only the value being decoded, such as a single report row, is held in memory.
+type streamDecoder interface {
+	decodeStream(dec *json.Decoder) error
+}
+
+// decodeStream reads the Status and the reports one row at a time.
+// Like json.Unmarshal, it goes on after a type mismatch and returns the first *json.UnmarshalTypeError at the end.
+func (e *reportEnvelope) decodeStream(dec *json.Decoder) error {
+	return decodeObject(dec, reflect.TypeOf(e), func(key string) error {
+		switch {
+		case strings.EqualFold(key, "Status"):
+			return dec.Decode(&e.Status) //nolint:wrapcheck // Wrapped by decode
+		case strings.EqualFold(key, "Reports"):
+			return decodeArray(dec, reflect.TypeOf(e.Reports), func() error {
+				e.Reports = append(e.Reports, Report{}) //nolint:exhaustruct // Filled by decodeReport
+
+				return decodeReport(dec, &e.Reports[len(e.Reports)-1])
+			})
+		default:
+			return skip(dec)
+		}
+	})
+}
+
+// decodeReport reads a report, one row at a time.
+func decodeReport(dec *json.Decoder, r *Report) error {
+	return decodeObject(dec, reflect.TypeOf(r), func(key string) error {
+		var target any
+
+		switch strings.ToLower(key) {
+		case "fields":
+			target = &r.Fields
+		case "reportid":
+			target = &r.ReportID
+		case "reportname":
+			target = &r.ReportName
+		case "reporttype":
+			target = &r.ReportType
+		case "reporttitles":
+			target = &r.ReportTitles
+		case "reportdate":
+			target = &r.ReportDate
+		case "updateddateutc":
+			target = &r.UpdatedDateUTC
+		case "rows":
+			return decodeArray(dec, reflect.TypeOf(r.Rows), func() error {
+				r.Rows = append(r.Rows, Row{}) //nolint:exhaustruct // Filled by Decode
+
+				return dec.Decode(&r.Rows[len(r.Rows)-1]) //nolint:wrapcheck // Wrapped by decode
+			})
+		default:
+			return skip(dec)
+		}
+
+		return dec.Decode(target) //nolint:wrapcheck // Wrapped by decode
+	})
+}
+
+// decodeObject reads a JSON object and calls field for each key, with the decoder positioned on the value.
+// Type mismatches do not stop the decoding, the first one is returned once the object is over.
+func decodeObject(dec *json.Decoder, typ reflect.Type, field func(key string) error) error {
+	return decodeComposite(dec, typ, '{', func() error {
+		token, err := dec.Token()
+		if err != nil {
+			return err //nolint:wrapcheck // Wrapped by decode
+		}
+
+		key, _ := token.(string)
+
+		return field(key)
+	})
+}
+
+// decodeArray reads a JSON array and calls elem for each element, with the decoder positioned on the element.
+// Type mismatches do not stop the decoding, the first one is returned once the array is over.
+func decodeArray(dec *json.Decoder, typ reflect.Type, elem func() error) error {
+	return decodeComposite(dec, typ, '[', elem)
+}
+
+// decodeComposite reads an object or an array, calling next until the closing delimiter.
+// A value of a different kind is skipped and reported as a *json.UnmarshalTypeError.
+func decodeComposite(dec *json.Decoder, typ reflect.Type, open json.Delim, next func() error) error {
+	offset := dec.InputOffset()
+
+	token, err := dec.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by decode
+	}
+
+	if token == nil {
+		return nil // Like json.Unmarshal, null leaves the value unchanged.
+	}
+
+	if token != open {
+		if err = skipFrom(dec, token); err != nil {
+			return err
+		}
+
+		return &json.UnmarshalTypeError{Value: kind(token), Type: typ, Offset: offset, Struct: "", Field: ""}
+	}
+
+	var mismatch error
+
+	for dec.More() {
+		if err = next(); err == nil {
+			continue
+		}
+
+		if !isTypeError(err) {
+			return err
+		}
+
+		mismatch = firstError(mismatch, err)
+	}
+
+	// Consume the closing delimiter.
+	if _, err = dec.Token(); err != nil {
+		return err //nolint:wrapcheck // Wrapped by decode
+	}
+
+	return mismatch
+}
+
+// skip discards the next value.
+func skip(dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err //nolint:wrapcheck // Wrapped by decode
+	}
+
+	return skipFrom(dec, token)
+}
+
+// skipFrom discards the rest of a value whose first token has already been read.
+func skipFrom(dec *json.Decoder, token json.Token) error {
+	for depth := 0; ; {
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+
+		var err error
+
+		if token, err = dec.Token(); err != nil {
+			return err //nolint:wrapcheck // Wrapped by decode
+		}
+	}
+}
+
+// isTypeError returns whether the error is a *json.UnmarshalTypeError.
+func isTypeError(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+
+	return errors.As(err, &typeErr)
+}
+
+// firstError returns the first non-nil error.
+func firstError(first, second error) error {
+	if first != nil {
+		return first
+	}
+
+	return second
+}
+
+// kind describes a JSON token as json.UnmarshalTypeError does.
+func kind(token json.Token) string {
+	switch token.(type) {
+	case json.Delim:
+		if token == json.Delim('{') {
+			return "object"
+		}
+
+		return "array"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	default:
+		return "number"
+	}
+}
@@ -0,0 +1,83 @@
+package xero
+
+import (
+	"context"
+)
+
+// tenantKey is the context key of the tenant ID.
+type tenantKey struct{}
+
+// CallOption customises a single API call.
+type CallOption func(*callOptions)
+
+// callOptions holds the settings of a single API call.
+type callOptions struct {
+	tenantID string
+}
+
+// Tenant sets the Xero tenant of a single call, it takes precedence over the context and the client's default tenant.
+func Tenant(tenantID string) CallOption {
+	return func(o *callOptions) {
+		o.tenantID = tenantID
+	}
+}
+
+// ContextWithTenant returns a copy of the context that carries the given Xero tenant ID.
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the Xero tenant ID carried by the context, if any.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+
+	return tenantID
+}
+
+// Connection is a Xero tenant (organisation or practice) the access token is authorised for.
+// See https://developer.xero.com/documentation/guides/oauth2/tenants
+type Connection struct {
+	ID             string `description:"Connection UUID" json:"id"`
+	AuthEventID    string `description:"UUID of the authorisation that created the connection" json:"authEventId"`
+	TenantID       string `description:"Tenant UUID, to be sent in the Xero-tenant-id header" json:"tenantId"`
+	TenantType     string `description:"Tenant type (ORGANISATION, PRACTICE)" json:"tenantType"`
+	TenantName     string `description:"Tenant human-readable name" json:"tenantName"`
+	CreatedDateUTC string `description:"Connection creation timestamp" json:"createdDateUtc"`
+	UpdatedDateUTC string `description:"Connection last update timestamp" json:"updatedDateUtc"`
+}
+
+// Connections invokes the Connections endpoint and returns the tenants the access token is authorised for.
+// See https://developer.xero.com/documentation/guides/oauth2/tenants#connections
+func (c *client) Connections(ctx context.Context) ([]Connection, error) {
+	// The Connections endpoint is not tenant-specific.
+	connections, err := fetch[[]Connection](ctx, c, call{path: "/connections", query: nil, tenantID: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	return *connections, nil
+}
+
+// tenant returns the tenant ID of a call, looking at the call options, the context and the client's default, in this order.
+func (c *client) tenant(ctx context.Context, opts []CallOption) (string, error) {
+	o := callOptions{tenantID: ""}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch {
+	case o.tenantID != "":
+		break
+	case TenantFromContext(ctx) != "":
+		o.tenantID = TenantFromContext(ctx)
+	default:
+		o.tenantID = c.tenantID
+	}
+
+	if err := validateID("Xero-tenant-id", o.tenantID); err != nil {
+		return "", err
+	}
+
+	return o.tenantID, nil
+}
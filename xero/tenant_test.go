@@ -0,0 +1,177 @@
+package xero_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantHeader(t *testing.T) {
+	t.Parallel()
+
+	const (
+		clientTenant  = "70784a63-d24b-46a9-a4db-0e70a274b056"
+		contextTenant = "e0da6937-de07-4a14-adee-37abfac298ce"
+		optionTenant  = "297c2dc5-cc47-4afd-8ec8-74990b8761e9"
+	)
+
+	tests := map[string]struct {
+		clientTenant  string
+		contextTenant string
+		opts          []xero.CallOption
+		header        string
+		err           error
+	}{
+		"no tenant": {
+			header: "",
+		},
+		"client default": {
+			clientTenant: clientTenant,
+			header:       clientTenant,
+		},
+		"context overrides client default": {
+			clientTenant:  clientTenant,
+			contextTenant: contextTenant,
+			header:        contextTenant,
+		},
+		"call option overrides context": {
+			clientTenant:  clientTenant,
+			contextTenant: contextTenant,
+			opts:          []xero.CallOption{xero.Tenant(optionTenant)},
+			header:        optionTenant,
+		},
+		"invalid tenant": {
+			opts: []xero.CallOption{xero.Tenant("my-org")},
+			err:  xero.ErrInvalidParam,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{
+				body:   fixture(t, "testdata/reports.json"),
+				status: http.StatusOK,
+			}
+
+			ctx := context.TODO()
+			if test.contextTenant != "" {
+				ctx = xero.ContextWithTenant(ctx, test.contextTenant)
+			}
+
+			_, err := xero.HTTPClient(nil).
+				WithHTTPClient(doer).
+				WithTenant(test.clientTenant).
+				BalanceSheet(ctx, xero.BalanceSheetParams{}, test.opts...)
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				assert.Empty(t, doer.requests)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.header, doer.requests[0].Header.Get("Xero-tenant-id"))
+		})
+	}
+}
+
+func TestTenantRateBudget(t *testing.T) {
+	t.Parallel()
+
+	doer := &recordingHTTPDoer{
+		body:   fixture(t, "testdata/reports.json"),
+		status: http.StatusOK,
+	}
+
+	client := xero.HTTPClient(nil).
+		WithHTTPClient(doer)
+
+	_, err := client.BalanceSheet(context.TODO(), xero.BalanceSheetParams{}, xero.Tenant("70784a63-d24b-46a9-a4db-0e70a274b056"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 59, client.RateBudget("70784a63-d24b-46a9-a4db-0e70a274b056").Minute)
+	assert.Equal(t, 60, client.RateBudget("e0da6937-de07-4a14-adee-37abfac298ce").Minute)
+	assert.Equal(t, 9999, client.RateBudget("e0da6937-de07-4a14-adee-37abfac298ce").AppMinute)
+}
+
+func TestConnections(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		body   []byte
+		status int
+		count  int
+		err    error
+	}{
+		"success": {
+			body:   fixture(t, "testdata/connections.json"),
+			status: http.StatusOK,
+			count:  2,
+		},
+		"invalid JSON": {
+			body:   []byte("hello"),
+			status: http.StatusOK,
+			err:    xero.ErrInvalidResponse,
+		},
+		"Xero down": {
+			body:   nil,
+			status: http.StatusServiceUnavailable,
+			err:    xero.ErrXeroDown,
+		},
+		"bad request": {
+			body:   nil,
+			status: http.StatusBadRequest,
+			err:    xero.ErrInvalidRequest,
+		},
+		"rate limited": {
+			body:   nil,
+			status: http.StatusTooManyRequests,
+			err:    xero.ErrTooManyRequests,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doer := &recordingHTTPDoer{
+				body:   test.body,
+				status: test.status,
+			}
+
+			connections, err := xero.HTTPClient(nil).
+				WithBaseURL("http://xero.test").
+				WithHTTPClient(doer).
+				WithTenant("70784a63-d24b-46a9-a4db-0e70a274b056").
+				Connections(context.TODO())
+
+			assert.Equal(t, "http://xero.test/connections", doer.requests[0].URL.String())
+			assert.Empty(t, doer.requests[0].Header.Get("Xero-tenant-id"))
+			assert.Equal(t, "application/json", doer.requests[0].Header.Get("Accept"))
+
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, connections, test.count)
+			assert.Equal(t, xero.Connection{
+				ID:             "e1eede29-f875-4a5d-8470-17f6a29a88b1",
+				AuthEventID:    "d99ecdfe-391d-43d2-b834-17636ba90e8d",
+				TenantID:       "70784a63-d24b-46a9-a4db-0e70a274b056",
+				TenantType:     "ORGANISATION",
+				TenantName:     "Maple Florists Ltd",
+				CreatedDateUTC: "2019-07-09T23:40:30.1833130",
+				UpdatedDateUTC: "2020-05-15T01:35:13.8491980",
+			}, connections[0])
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package xero
+
+import (
+	"time"
+)
+
+// periodDateFormats are the layouts used by Xero in the Header row of the reports.
+var periodDateFormats = []string{ //nolint:gochecknoglobals // Read-only list of layouts
+	"2 January 2006",
+	"2 Jan 2006",
+	"2 Jan 06",
+}
+
+// periodMonthFormats are the layouts used by Xero for monthly columns, such as in the Executive Summary.
+var periodMonthFormats = []string{ //nolint:gochecknoglobals // Read-only list of layouts
+	"January 2006",
+	"Jan 2006",
+}
+
+// ReportTree is an analysed view of a Report, where period columns are resolved and cell values parsed.
+type ReportTree struct {
+	Name     string    `description:"Report human-readable label" json:"name"`
+	Type     string    `description:"Report type (BalanceSheet, ProfitAndLoss, ...)" json:"type"`
+	Titles   []string  `description:"Report titles" json:"titles"`
+	Periods  []Period  `description:"Value columns, as listed in the Header row" json:"periods"`
+	Sections []Section `description:"Report sections, in order" json:"sections"`
+}
+
+// Period is a value column of a report.
+type Period struct {
+	Label string    `description:"Column header as returned by Xero (25 August 2024, Debit, ...)" json:"label"`
+	Date  time.Time `description:"Date the column refers to, zero if the header is not a date" json:"date"`
+}
+
+// Section is a group of lines, optionally followed by their summary and the sections nested in it.
+type Section struct {
+	Title    string    `description:"Section title, may be empty" json:"title"`
+	Lines    []Line    `description:"Account lines" json:"lines"`
+	Summary  *Line     `description:"Summary row, nil if Xero did not return one" json:"summary"`
+	Sections []Section `description:"Sections nested in this one, in order" json:"sections"`
+}
+
+// Line is an account or summary row of a report.
+type Line struct {
+	Label     string     `description:"Row label, i.e. the first cell" json:"label"`
+	AccountID string     `description:"Account UUID, empty for rows that are not accounts" json:"accountId,omitempty"`
+	Amounts   []*Decimal `description:"One amount per period, nil for empty or non-numeric cells" json:"amounts"`
+	Values    []string   `description:"One cell value per period, as returned by Xero" json:"values"`
+}
+
+// Amount returns the amount of the line in the given period, or nil if there is none.
+func (l Line) Amount(period int) *Decimal {
+	if period < 0 || period >= len(l.Amounts) {
+		return nil
+	}
+
+	return l.Amounts[period]
+}
+
+// Tree returns the analysed view of the report.
+// The first column holds the row labels, every other column is a Period whose amounts are parsed as by ParseMoney.
+// Rows found outside a section are grouped in sections without title, nested sections are kept under their parent.
+func (r Report) Tree() ReportTree {
+	tree := ReportTree{
+		Name:     r.ReportName,
+		Type:     r.ReportType,
+		Titles:   r.ReportTitles,
+		Periods:  []Period{},
+		Sections: []Section{},
+	}
+
+	var loose *Section
+
+	for _, row := range r.Rows {
+		switch row.RowType {
+		case "Header":
+			tree.Periods = periods(row)
+		case "Section":
+			loose = nil
+			tree.Sections = append(tree.Sections, newSection(row))
+		case "Row", "SummaryRow":
+			if loose == nil {
+				tree.Sections = append(tree.Sections, Section{Title: "", Lines: []Line{}, Summary: nil, Sections: []Section{}})
+				loose = &tree.Sections[len(tree.Sections)-1]
+			}
+
+			loose.add(row)
+		}
+	}
+
+	return tree
+}
+
+// AllSections returns the sections of the report and the ones nested in them, each followed by its nested sections.
+func (t ReportTree) AllSections() []Section {
+	return flatten(t.Sections)
+}
+
+// flatten lists the sections, each followed by its nested sections.
+func flatten(sections []Section) []Section {
+	all := []Section{}
+
+	for _, section := range sections {
+		all = append(all, section)
+		all = append(all, flatten(section.Sections)...)
+	}
+
+	return all
+}
+
+// add appends the row to the section.
+func (s *Section) add(row Row) {
+	line := newLine(row)
+
+	if row.RowType == "SummaryRow" {
+		s.Summary = &line
+
+		return
+	}
+
+	s.Lines = append(s.Lines, line)
+}
+
+// newLine reads the label, account and amounts of a row.
+func newLine(row Row) Line {
+	line := Line{Label: "", AccountID: "", Amounts: []*Decimal{}, Values: []string{}}
+
+	for i, cell := range row.Cells {
+		if i == 0 {
+			line.Label = cell.Value
+			line.AccountID = accountID(cell)
+
+			continue
+		}
+
+		if money, err := ParseMoney(cell.Value, ""); err == nil {
+			line.Amounts = append(line.Amounts, &money.Amount)
+		} else {
+			line.Amounts = append(line.Amounts, nil)
+		}
+
+		line.Values = append(line.Values, cell.Value)
+
+		if line.AccountID == "" {
+			line.AccountID = accountID(cell)
+		}
+	}
+
+	return line
+}
+
+// accountID returns the value of the "account" attribute of the cell.
+func accountID(cell Cell) string {
+	for _, attr := range cell.Attributes {
+		if attr.ID == "account" {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// periods reads the period columns from the Header row.
+func periods(header Row) []Period {
+	periods := []Period{}
+
+	for i, cell := range header.Cells {
+		if i > 0 {
+			periods = append(periods, Period{Label: cell.Value, Date: periodDate(cell.Value)})
+		}
+	}
+
+	return periods
+}
+
+// periodDate parses a column header, months resolve to their last day. It returns the zero time for other headers.
+func periodDate(label string) time.Time {
+	for _, layout := range periodDateFormats {
+		if date, err := time.Parse(layout, label); err == nil {
+			return date
+		}
+	}
+
+	for _, layout := range periodMonthFormats {
+		if date, err := time.Parse(layout, label); err == nil {
+			return date.AddDate(0, 1, -1)
+		}
+	}
+
+	return time.Time{}
+}
+
+// newSection reads a Section row, and the sections nested in it.
+func newSection(row Row) Section {
+	section := Section{Title: row.Title, Lines: []Line{}, Summary: nil, Sections: []Section{}}
+
+	for _, child := range row.Rows {
+		switch child.RowType {
+		case "Section":
+			section.Sections = append(section.Sections, newSection(child))
+		case "Row", "SummaryRow":
+			section.add(child)
+		}
+	}
+
+	return section
+}
@@ -0,0 +1,153 @@
+package xero_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportTree(t *testing.T) {
+	t.Parallel()
+
+	tree := reportTree(t, "testdata/profit-and-loss.json")
+
+	assert.Equal(t, "Profit and Loss", tree.Name)
+	assert.Equal(t, "ProfitAndLoss", tree.Type)
+	assert.Equal(t, []xero.Period{
+		{Label: "31 Jul 24", Date: time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)},
+	}, tree.Periods)
+	assert.Len(t, tree.Sections, 3)
+
+	income := tree.Sections[0]
+
+	assert.Equal(t, "Income", income.Title)
+	assert.Len(t, income.Lines, 1)
+	assert.Equal(t, "Sales", income.Lines[0].Label)
+	assert.Equal(t, "e2bacdc6-2006-43c2-a5da-3c0e5f43b452", income.Lines[0].AccountID)
+	assert.Equal(t, "4500.00", income.Lines[0].Amount(0).String())
+	assert.Nil(t, income.Lines[0].Amount(1))
+	assert.Equal(t, "Total Income", income.Summary.Label)
+	assert.Equal(t, "4500.00", income.Summary.Amount(0).String())
+
+	assert.Empty(t, tree.Sections[2].Title)
+	assert.Nil(t, tree.Sections[2].Summary)
+	assert.Equal(t, "Net Profit", tree.Sections[2].Lines[0].Label)
+}
+
+func TestReportTreeNestedSections(t *testing.T) {
+	t.Parallel()
+
+	report := xero.Report{
+		ReportName: "Balance Sheet",
+		Rows: []xero.Row{
+			{RowType: "Header", Cells: []xero.Cell{{Value: ""}, {Value: "31 Aug 2024"}}},
+			{RowType: "Section", Title: "Assets", Rows: []xero.Row{
+				{RowType: "Section", Title: "Bank", Rows: []xero.Row{
+					{RowType: "Row", Cells: []xero.Cell{{Value: "Business Account"}, {Value: "100.00"}}},
+				}},
+				{RowType: "Section", Title: "Current Assets", Rows: []xero.Row{
+					{RowType: "Row", Cells: []xero.Cell{{Value: "Accounts Receivable"}, {Value: "50.00"}}},
+				}},
+				{RowType: "SummaryRow", Cells: []xero.Cell{{Value: "Total Assets"}, {Value: "150.00"}}},
+			}},
+		},
+	}
+
+	tree := report.Tree()
+
+	assert.Len(t, tree.Sections, 1)
+
+	assets := tree.Sections[0]
+
+	assert.Equal(t, "Assets", assets.Title)
+	assert.Empty(t, assets.Lines)
+	assert.Equal(t, "150.00", assets.Summary.Amount(0).String())
+	assert.Len(t, assets.Sections, 2)
+	assert.Equal(t, "Bank", assets.Sections[0].Title)
+	assert.Equal(t, "Business Account", assets.Sections[0].Lines[0].Label)
+	assert.Equal(t, []string{"100.00"}, assets.Sections[0].Lines[0].Values)
+	assert.Equal(t, "Current Assets", assets.Sections[1].Title)
+
+	titles := []string{}
+
+	for _, section := range tree.AllSections() {
+		titles = append(titles, section.Title)
+	}
+
+	assert.Equal(t, []string{"Assets", "Bank", "Current Assets"}, titles)
+}
+
+func TestReportTreePeriods(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fixture string
+		periods []xero.Period
+	}{
+		"balance sheet": {
+			fixture: "testdata/reports.json",
+			periods: []xero.Period{
+				{Label: "25 August 2024", Date: time.Date(2024, 8, 25, 0, 0, 0, 0, time.UTC)},
+				{Label: "26 August 2023", Date: time.Date(2023, 8, 26, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		"executive summary - months and variance": {
+			fixture: "testdata/executive-summary.json",
+			periods: []xero.Period{
+				{Label: "Aug 2024", Date: time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)},
+				{Label: "Jul 2024", Date: time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)},
+				{Label: "Variance", Date: time.Time{}},
+			},
+		},
+		"trial balance - no dates": {
+			fixture: "testdata/trial-balance.json",
+			periods: []xero.Period{
+				{Label: "Debit", Date: time.Time{}},
+				{Label: "Credit", Date: time.Time{}},
+				{Label: "YTD Debit", Date: time.Time{}},
+				{Label: "YTD Credit", Date: time.Time{}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.periods, reportTree(t, test.fixture).Periods)
+		})
+	}
+}
+
+func TestReportTreeAmounts(t *testing.T) {
+	t.Parallel()
+
+	tree := reportTree(t, "testdata/executive-summary.json")
+	cash := tree.Sections[0].Lines[0]
+
+	assert.Equal(t, "Cash received", cash.Label)
+	assert.Len(t, cash.Amounts, 3)
+	assert.Equal(t, "3900.00", cash.Amount(1).String())
+	assert.Nil(t, cash.Amount(2), "percentages are not amounts")
+
+	tree = reportTree(t, "testdata/trial-balance.json")
+	sales := tree.Sections[0].Lines[0]
+
+	assert.Nil(t, sales.Amount(0), "empty cells have no amount")
+	assert.Equal(t, "4500.00", sales.Amount(1).String())
+}
+
+func reportTree(t *testing.T, path string) xero.ReportTree {
+	t.Helper()
+
+	var rr xero.ReportResponse
+
+	if err := json.Unmarshal(fixture(t, path), &rr); err != nil {
+		t.Fatal(err)
+	}
+
+	return rr.Reports[0].Tree()
+}
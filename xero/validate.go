@@ -0,0 +1,158 @@
+package xero
+
+import (
+	"strings"
+)
+
+// Checks performed by ValidateBalanceSheet, as reported in Finding.Check.
+const (
+	CheckBalance      = "balance"      // Total Assets equals Total Liabilities plus Total Equity.
+	CheckMissingTotal = "missingTotal" // A total needed by the balance check is not in the report.
+	CheckSectionTotal = "sectionTotal" // The lines of a section sum to its SummaryRow.
+)
+
+// Finding is an inconsistency found in a report.
+type Finding struct {
+	Check    string   `description:"Failed check (balance, missingTotal, sectionTotal)" json:"check"`
+	Section  string   `description:"Section title, for section checks" json:"section,omitempty"`
+	Period   string   `description:"Period column label, empty if the finding applies to the whole report" json:"period,omitempty"`
+	Expected *Decimal `description:"Amount stated in the report" json:"expected,omitempty"`
+	Actual   *Decimal `description:"Amount computed from the report lines" json:"actual,omitempty"`
+	Message  string   `description:"Human-readable description" json:"message"`
+}
+
+// ValidateBalanceSheet checks that the lines of each section sum to its SummaryRow, and that Total Assets equals
+// Total Liabilities plus Total Equity for every period column. Amounts are compared exactly.
+// A missing Total Liabilities or Total Equity counts as zero, a missing Total Assets is reported as a finding.
+func ValidateBalanceSheet(report Report) []Finding {
+	tree := report.Tree()
+	findings := []Finding{}
+
+	for _, section := range tree.AllSections() {
+		findings = append(findings, validateSection(tree, section)...)
+	}
+
+	return append(findings, validateBalance(tree)...)
+}
+
+// validateSection checks that the lines of the section sum to its summary in every period.
+func validateSection(tree ReportTree, section Section) []Finding {
+	findings := []Finding{}
+
+	if section.Summary == nil {
+		return findings
+	}
+
+	for period := range columns(tree, section.Summary) {
+		expected := section.Summary.Amount(period)
+		if expected == nil {
+			continue
+		}
+
+		var actual Decimal
+
+		for _, line := range section.Lines {
+			if amount := line.Amount(period); amount != nil {
+				actual = actual.Add(*amount)
+			}
+		}
+
+		if actual.Cmp(*expected) != 0 {
+			findings = append(findings, Finding{
+				Check:    CheckSectionTotal,
+				Section:  section.Title,
+				Period:   periodLabel(tree, period),
+				Expected: expected,
+				Actual:   &actual,
+				Message:  section.Summary.Label + " is " + expected.String() + " but the lines sum to " + actual.String(),
+			})
+		}
+	}
+
+	return findings
+}
+
+// validateBalance checks the accounting equation in every period.
+func validateBalance(tree ReportTree) []Finding {
+	assets := findTotal(tree, "Total Assets")
+	if assets == nil {
+		return []Finding{{
+			Check:    CheckMissingTotal,
+			Section:  "",
+			Period:   "",
+			Expected: nil,
+			Actual:   nil,
+			Message:  "the report has no Total Assets line",
+		}}
+	}
+
+	findings := []Finding{}
+	liabilities := findTotal(tree, "Total Liabilities")
+	equity := findTotal(tree, "Total Equity")
+
+	for period := range columns(tree, assets) {
+		expected := assets.Amount(period)
+		if expected == nil {
+			continue
+		}
+
+		actual := lineAmount(liabilities, period).Add(lineAmount(equity, period))
+
+		if actual.Cmp(*expected) != 0 {
+			findings = append(findings, Finding{
+				Check:    CheckBalance,
+				Section:  "",
+				Period:   periodLabel(tree, period),
+				Expected: expected,
+				Actual:   &actual,
+				Message:  "Total Assets is " + expected.String() + " but Total Liabilities plus Total Equity is " + actual.String(),
+			})
+		}
+	}
+
+	return findings
+}
+
+// columns returns the number of period columns to check for the line.
+func columns(tree ReportTree, line *Line) int {
+	if len(tree.Periods) > 0 {
+		return len(tree.Periods)
+	}
+
+	return len(line.Amounts)
+}
+
+// findTotal returns the line or summary with the given label, ignoring case.
+func findTotal(tree ReportTree, label string) *Line {
+	for _, section := range tree.AllSections() {
+		if section.Summary != nil && strings.EqualFold(section.Summary.Label, label) {
+			return section.Summary
+		}
+
+		for i := range section.Lines {
+			if strings.EqualFold(section.Lines[i].Label, label) {
+				return &section.Lines[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// lineAmount returns the amount of the line in the period, zero if either is missing.
+func lineAmount(line *Line, period int) Decimal {
+	if line == nil || line.Amount(period) == nil {
+		return Decimal{}
+	}
+
+	return *line.Amount(period)
+}
+
+// periodLabel returns the label of the period column.
+func periodLabel(tree ReportTree, period int) string {
+	if period < len(tree.Periods) {
+		return tree.Periods[period].Label
+	}
+
+	return ""
+}
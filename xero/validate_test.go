@@ -0,0 +1,102 @@
+package xero_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/luca-arch/code-drills/xero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBalanceSheet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		edit     func(rows []xero.Row)
+		findings []string // Check, section and period of each finding.
+	}{
+		"consistent report": {
+			edit:     func([]xero.Row) {},
+			findings: []string{},
+		},
+		"line does not add up": {
+			edit: func(rows []xero.Row) {
+				rows[2].Rows[0].Cells[1].Value = "18340.01" // Business Bank Account
+			},
+			findings: []string{"sectionTotal Bank 31 Aug 2024"},
+		},
+		"summary does not match lines nor balance": {
+			edit: func(rows []xero.Row) {
+				rows[10].Rows[2].Cells[2].Value = "27000.00" // Total Equity
+			},
+			findings: []string{"sectionTotal Equity 31 Aug 2023", "balance  31 Aug 2023"},
+		},
+		"assets do not balance": {
+			edit: func(rows []xero.Row) {
+				rows[5].Rows[0].Cells[1].Value = "(31,590.00)" // Total Assets
+			},
+			findings: []string{"balance  31 Aug 2024"},
+		},
+		"no Total Assets": {
+			edit: func(rows []xero.Row) {
+				rows[5].Rows = nil
+			},
+			findings: []string{"missingTotal  "},
+		},
+		"no liabilities": {
+			edit: func(rows []xero.Row) {
+				rows[8].Rows = nil // Total Liabilities
+			},
+			findings: []string{"balance  31 Aug 2024", "balance  31 Aug 2023"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var rr xero.ReportResponse
+
+			if err := json.Unmarshal(fixture(t, "testdata/balance-sheet.json"), &rr); err != nil {
+				t.Fatal(err)
+			}
+
+			test.edit(rr.Reports[0].Rows)
+
+			got := []string{}
+			for _, finding := range xero.ValidateBalanceSheet(rr.Reports[0]) {
+				got = append(got, finding.Check+" "+finding.Section+" "+finding.Period)
+			}
+
+			assert.Equal(t, test.findings, got)
+		})
+	}
+}
+
+func TestValidateBalanceSheetFinding(t *testing.T) {
+	t.Parallel()
+
+	var rr xero.ReportResponse
+
+	if err := json.Unmarshal(fixture(t, "testdata/balance-sheet.json"), &rr); err != nil {
+		t.Fatal(err)
+	}
+
+	rr.Reports[0].Rows[3].Rows[0].Cells[1].Value = "1,000.00" // Accounts Receivable
+
+	findings := xero.ValidateBalanceSheet(rr.Reports[0])
+
+	assert.Len(t, findings, 1)
+
+	data, err := json.Marshal(findings[0])
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"check": "sectionTotal",
+		"section": "Current Assets",
+		"period": "31 Aug 2024",
+		"expected": 1250.00,
+		"actual": 1000.00,
+		"message": "Total Current Assets is 1250.00 but the lines sum to 1000.00"
+	}`, string(data))
+}